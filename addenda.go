@@ -28,6 +28,8 @@ type Addenda struct {
 	// the same as the last seven digits of the trace number of the related
 	// Entry Detail Record or Corporate Entry Detail Record.
 	EntryDetailSequenceNumber int
+	// Raw is the record as it was read when the Reader has the PreserveRawRecords option
+	Raw *RawRecord
 	// validator is composed for data validation
 	validator
 	// converters is composed for ACH to GoLang Converters
@@ -56,7 +58,9 @@ func NewAddenda(params ...AddendaParam) Addenda {
 }
 
 // Parse takes the input record string and parses the Addenda values
+// A record shorter than RecordLength is padded with spaces.
 func (addenda *Addenda) Parse(record string) {
+	record = addenda.padRecord(record)
 	// 1-1 Always "7"
 	addenda.recordType = "7"
 	// 2-3 Defines the specific explanation and format for the addenda information contained in the same record
@@ -0,0 +1,122 @@
+// Copyright 2017 The ACH Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package ach
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Addenda98 is a Notification of Change (NOC) addenda record. It is sent by the RDFI
+// to inform the originator of information in an entry that should be corrected.
+type Addenda98 struct {
+	// RecordType defines the type of record in the block. entryAddendaPos 7
+	recordType string
+	// TypeCode Addenda types code '98'
+	TypeCode string
+	// ChangeCode identifies the information being corrected. For example "C01"
+	// is an incorrect DFI account number
+	ChangeCode string
+	// OriginalTrace is the trace number of the entry being corrected
+	OriginalTrace int
+	// OriginalDFI is the RDFI identification of the entry being corrected
+	OriginalDFI string
+	// CorrectedData is the corrected information. The layout of the data depends on ChangeCode
+	CorrectedData string
+	// Trace is the trace number of the NOC entry
+	Trace int
+	// Raw is the record as it was read when the Reader has the PreserveRawRecords option
+	Raw *RawRecord
+
+	// validator is composed for data validation
+	validator
+	// converters is composed for ACH to GoLang Converters
+	converters
+}
+
+// Errors specific to an Addenda98 record
+var (
+	msgAddenda98ChangeCode    = "%v change code can not be applied to an entry"
+	msgAddenda98CorrectedData = "is blank for change code %v"
+	msgAddenda98ChangeCodeNOC = "%v is not a change code C01 through C13"
+)
+
+// isChangeCode returns true if code is one of the change codes C01 through C13 of a
+// Notification of Change
+func isChangeCode(code string) bool {
+	switch code {
+	case "C01", "C02", "C03", "C04", "C05", "C06", "C07", "C08", "C09", "C10", "C11", "C12", "C13":
+		return true
+	}
+	return false
+}
+
+// NewAddenda98 returns a new Addenda98 with default values for none exported fields
+func NewAddenda98() *Addenda98 {
+	return &Addenda98{
+		recordType: "7",
+		TypeCode:   "98",
+	}
+}
+
+// Parse takes the input record string and parses the Addenda98 values
+// A record shorter than RecordLength is padded with spaces.
+func (addenda98 *Addenda98) Parse(record string) {
+	record = addenda98.padRecord(record)
+	// 1-1 Always "7"
+	addenda98.recordType = "7"
+	// 2-3 Always "98"
+	addenda98.TypeCode = record[1:3]
+	// 4-6
+	addenda98.ChangeCode = record[3:6]
+	// 7-21
+	addenda98.OriginalTrace = addenda98.parseNumField(record[6:21])
+	// 22-27 reserved
+	// 28-35
+	addenda98.OriginalDFI = record[27:35]
+	// 36-64
+	addenda98.CorrectedData = strings.TrimSpace(record[35:64])
+	// 65-79 reserved
+	// 80-94
+	addenda98.Trace = addenda98.parseNumField(record[79:94])
+}
+
+// String writes the Addenda98 struct to a 94 character string.
+func (addenda98 *Addenda98) String() string {
+	return fmt.Sprintf("%v%v%v%v%v%v%v%v%v",
+		addenda98.recordType,
+		addenda98.TypeCode,
+		addenda98.alphaField(addenda98.ChangeCode, 3),
+		addenda98.numericField(addenda98.OriginalTrace, 15),
+		strings.Repeat(" ", 6),
+		addenda98.alphaField(addenda98.OriginalDFI, 8),
+		addenda98.CorrectedDataField(),
+		strings.Repeat(" ", 15),
+		addenda98.numericField(addenda98.Trace, 15))
+}
+
+// Validate performs NACHA format rule checks on the record and returns an error if not Validated
+// The first error encountered is returned and stops that parsing.
+func (addenda98 *Addenda98) Validate() error {
+	if addenda98.recordType != "7" {
+		msg := fmt.Sprintf(msgRecordType, 7)
+		return &FieldError{FieldName: "recordType", Value: addenda98.recordType, Msg: msg}
+	}
+	if addenda98.TypeCode != "98" {
+		return &FieldError{FieldName: "TypeCode", Value: addenda98.TypeCode, Msg: msgAddendaTypeCode}
+	}
+	if addenda98.ChangeCode == "" {
+		return &FieldError{FieldName: "ChangeCode", Value: addenda98.ChangeCode, Msg: msgFieldInclusion}
+	}
+	if err := addenda98.isAlphanumeric(addenda98.CorrectedData); err != nil {
+		return &FieldError{FieldName: "CorrectedData", Value: addenda98.CorrectedData, Msg: err.Error()}
+	}
+	return nil
+}
+
+// CorrectedDataField returns a space padded CorrectedData string
+func (addenda98 *Addenda98) CorrectedDataField() string {
+	return addenda98.alphaField(addenda98.CorrectedData, 29)
+}
@@ -0,0 +1,60 @@
+// Copyright 2017 The ACH Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package ach
+
+import (
+	"testing"
+)
+
+func mockAddenda98() *Addenda98 {
+	addenda98 := NewAddenda98()
+	addenda98.ChangeCode = "C01"
+	addenda98.OriginalTrace = 123456789
+	addenda98.OriginalDFI = "09101298"
+	addenda98.CorrectedData = "1918171614"
+	addenda98.Trace = 91012980000088
+	return addenda98
+}
+
+func TestAddenda98Parse(t *testing.T) {
+	line := "798C01000000123456789      09101298C00000001918171614                          091012980000088"
+	addenda98 := NewAddenda98()
+	addenda98.Parse(line)
+	if addenda98.ChangeCode != "C01" {
+		t.Errorf("ChangeCode Expected 'C01' got: %v", addenda98.ChangeCode)
+	}
+	if addenda98.OriginalTrace != 123456789 {
+		t.Errorf("OriginalTrace Expected 123456789 got: %v", addenda98.OriginalTrace)
+	}
+	if addenda98.OriginalDFI != "09101298" {
+		t.Errorf("OriginalDFI Expected '09101298' got: %v", addenda98.OriginalDFI)
+	}
+	if addenda98.CorrectedData != "C00000001918171614" {
+		t.Errorf("CorrectedData Expected 'C00000001918171614' got: %v", addenda98.CorrectedData)
+	}
+	if addenda98.Trace != 91012980000088 {
+		t.Errorf("Trace Expected 91012980000088 got: %v", addenda98.Trace)
+	}
+	if addenda98.String() != line {
+		t.Errorf("Strings do not match")
+	}
+	if err := addenda98.Validate(); err != nil {
+		t.Errorf("%T: %s", err, err)
+	}
+}
+
+func TestAddenda98ChangeCodeInclusion(t *testing.T) {
+	addenda98 := mockAddenda98()
+	addenda98.ChangeCode = ""
+	if err := addenda98.Validate(); err != nil {
+		if e, ok := err.(*FieldError); ok {
+			if e.FieldName != "ChangeCode" {
+				t.Errorf("%T: %s", err, err)
+			}
+		}
+	} else {
+		t.Error("expected error for blank ChangeCode")
+	}
+}
@@ -0,0 +1,156 @@
+// Copyright 2017 The ACH Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package ach
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ADVBatchControl contains entry counts, dollar totals and hash totals for all
+// ADV entries contained in the preceding batch. ADV batch controls carry 20 digit
+// dollar totals in place of the company identification.
+type ADVBatchControl struct {
+	// RecordType defines the type of record in the block. batchControlPos 8
+	recordType string
+	// ServiceClassCode is 280 or 200 for Automated Accounting Advices
+	ServiceClassCode int
+	// EntryAddendaCount is a tally of each ADV Entry Detail Record within the batch
+	EntryAddendaCount int
+	// EntryHash is the sum of the RDFIIdentification fields of the ADV Entry Detail Records in the batch
+	EntryHash int
+	// TotalDebitEntryDollarAmount Contains accumulated Entry debit totals within the batch.
+	TotalDebitEntryDollarAmount int
+	// TotalCreditEntryDollarAmount Contains accumulated Entry credit totals within the batch.
+	TotalCreditEntryDollarAmount int
+	// ACHOperatorData is used by the ACH operator
+	ACHOperatorData string
+	// ODFIIdentification the routing number is used to identify the DFI originating entries within a given branch.
+	ODFIIdentification int
+	// BatchNumber is the same as the batch number in the Batch Header Record
+	BatchNumber int
+	// validator is composed for data validation
+	validator
+	// converters is composed for ACH to golang Converters
+	converters
+}
+
+// NewADVBatchControl returns a new ADVBatchControl with default values for none exported fields
+func NewADVBatchControl() *ADVBatchControl {
+	return &ADVBatchControl{
+		recordType:       "8",
+		ServiceClassCode: 280,
+		EntryHash:        1,
+		BatchNumber:      1,
+	}
+}
+
+// Parse takes the input record string and parses the ADVBatchControl values
+// A record shorter than RecordLength is padded with spaces.
+func (bc *ADVBatchControl) Parse(record string) {
+	record = bc.padRecord(record)
+	// 1-1 Always "8"
+	bc.recordType = "8"
+	// 2-4 This is the same as the "Service code" field in previous Batch Header Record
+	bc.ServiceClassCode = bc.parseNumField(record[1:4])
+	// 5-10 Total number of ADV Entry Detail Records in the batch
+	bc.EntryAddendaCount = bc.parseNumField(record[4:10])
+	// 11-20 Total of all positions 4-11 on each ADV Entry Detail Record in the batch
+	bc.EntryHash = bc.parseNumField(record[10:20])
+	// 21-40 Number of cents of debit entries within the batch
+	bc.TotalDebitEntryDollarAmount = bc.parseNumField(record[20:40])
+	// 41-60 Number of cents of credit entries within the batch
+	bc.TotalCreditEntryDollarAmount = bc.parseNumField(record[40:60])
+	// 61-79 ACH operator data
+	bc.ACHOperatorData = strings.TrimSpace(record[60:79])
+	// 80-87 This is the same as the "ODFI identification" field in previous Batch Header Record
+	bc.ODFIIdentification = bc.parseNumField(record[79:87])
+	// 88-94 This is the same as the "Batch number" field in previous Batch Header Record
+	bc.BatchNumber = bc.parseNumField(record[87:94])
+}
+
+// String writes the ADVBatchControl struct to a 94 character string.
+func (bc *ADVBatchControl) String() string {
+	return fmt.Sprintf("%v%v%v%v%v%v%v%v%v",
+		bc.recordType,
+		bc.ServiceClassCode,
+		bc.EntryAddendaCountField(),
+		bc.EntryHashField(),
+		bc.TotalDebitEntryDollarAmountField(),
+		bc.TotalCreditEntryDollarAmountField(),
+		bc.ACHOperatorDataField(),
+		bc.ODFIIdentificationField(),
+		bc.BatchNumberField(),
+	)
+}
+
+// Validate performs NACHA format rule checks on the record and returns an error if not Validated
+// The first error encountered is returned and stops that parsing.
+func (bc *ADVBatchControl) Validate() error {
+	if err := bc.fieldInclusion(); err != nil {
+		return err
+	}
+	if bc.recordType != "8" {
+		msg := fmt.Sprintf(msgRecordType, 8)
+		return &FieldError{FieldName: "recordType", Value: bc.recordType, Msg: msg}
+	}
+	if bc.ServiceClassCode != 280 && bc.ServiceClassCode != 200 {
+		return &FieldError{FieldName: "ServiceClassCode", Value: strconv.Itoa(bc.ServiceClassCode), Msg: msgServiceClass}
+	}
+	if err := bc.isAlphanumeric(bc.ACHOperatorData); err != nil {
+		return &FieldError{FieldName: "ACHOperatorData", Value: bc.ACHOperatorData, Msg: err.Error()}
+	}
+	return nil
+}
+
+// fieldInclusion validate mandatory fields are not default values.
+func (bc *ADVBatchControl) fieldInclusion() error {
+	if bc.recordType == "" {
+		return &FieldError{FieldName: "recordType", Value: bc.recordType, Msg: msgFieldInclusion}
+	}
+	if bc.ServiceClassCode == 0 {
+		return &FieldError{FieldName: "ServiceClassCode", Value: strconv.Itoa(bc.ServiceClassCode), Msg: msgFieldInclusion}
+	}
+	if bc.ODFIIdentification == 0 {
+		return &FieldError{FieldName: "ODFIIdentification", Value: bc.ODFIIdentificationField(), Msg: msgFieldInclusion}
+	}
+	return nil
+}
+
+// EntryAddendaCountField gets a string of the entry count zero padded
+func (bc *ADVBatchControl) EntryAddendaCountField() string {
+	return bc.numericField(bc.EntryAddendaCount, 6)
+}
+
+// EntryHashField get a zero padded EntryHash
+func (bc *ADVBatchControl) EntryHashField() string {
+	return bc.numericField(bc.EntryHash, 10)
+}
+
+// TotalDebitEntryDollarAmountField get a zero padded Debit Entry Amount
+func (bc *ADVBatchControl) TotalDebitEntryDollarAmountField() string {
+	return bc.numericField(bc.TotalDebitEntryDollarAmount, 20)
+}
+
+// TotalCreditEntryDollarAmountField get a zero padded Credit Entry Amount
+func (bc *ADVBatchControl) TotalCreditEntryDollarAmountField() string {
+	return bc.numericField(bc.TotalCreditEntryDollarAmount, 20)
+}
+
+// ACHOperatorDataField get the ACHOperatorData right padded
+func (bc *ADVBatchControl) ACHOperatorDataField() string {
+	return bc.alphaField(bc.ACHOperatorData, 19)
+}
+
+// ODFIIdentificationField get the odfi number zero padded
+func (bc *ADVBatchControl) ODFIIdentificationField() string {
+	return bc.numericField(bc.ODFIIdentification, 8)
+}
+
+// BatchNumberField gets a string of the batch number zero padded
+func (bc *ADVBatchControl) BatchNumberField() string {
+	return bc.numericField(bc.BatchNumber, 7)
+}
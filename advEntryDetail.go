@@ -0,0 +1,239 @@
+// Copyright 2017 The ACH Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package ach
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Errors specific to an ADV Entry Detail Record
+var (
+	msgADVTransactionCode = "is not a valid automated accounting advice transaction code 81 through 88"
+)
+
+// ADVEntryDetail is an Automated Accounting Advice entry detail record. ADV entries are sent by
+// an ACH operator to a financial institution to report the ACH activity settled to its accounts.
+type ADVEntryDetail struct {
+	// RecordType defines the type of record in the block. entryDetailPos 6
+	recordType string
+	// TransactionCode identifies the type of advice:
+	// 81 Credit for ACH debits originated
+	// 82 Debit for ACH credits originated
+	// 83 Credit for ACH credits received
+	// 84 Debit for ACH debits received
+	// 85 Credit for ACH credits in rejected batches
+	// 86 Debit for ACH debits in rejected batches
+	// 87 Summary credit for respondent ACH activity
+	// 88 Summary debit for respondent ACH activity
+	TransactionCode int
+	// RDFIIdentification is the first 8 digits of the routing number of the financial
+	// institution receiving the advice
+	RDFIIdentification int
+	// CheckDigit the last digit of the RDFI's routing number
+	CheckDigit int
+	// DFIAccountNumber is the account number of the receiving financial institution
+	DFIAccountNumber string
+	// Amount of the advice in cents
+	Amount int
+	// AdviceRoutingNumber is the routing number of the financial institution the advice is for
+	AdviceRoutingNumber string
+	// FileIdentification identifies the file the advice summarizes
+	FileIdentification string
+	// ACHOperatorData is used by the ACH operator
+	ACHOperatorData string
+	// IndividualName is the name of the receiving financial institution
+	IndividualName string
+	// DiscretionaryData is used by the ACH operator
+	DiscretionaryData string
+	// AddendaRecordIndicator is always 0 as ADV entries do not have addenda
+	AddendaRecordIndicator int
+	// ACHOperatorRoutingNumber is the routing number of the ACH operator
+	ACHOperatorRoutingNumber string
+	// JulianDay is the day of the year the advice was created
+	JulianDay int
+	// SequenceNumber is the sequence number of the advice within the ACH operator's day
+	SequenceNumber int
+
+	// validator is composed for data validation
+	validator
+	// converters is composed for ACH to golang Converters
+	converters
+}
+
+// NewADVEntryDetail returns a new ADVEntryDetail with default values for none exported fields
+func NewADVEntryDetail() *ADVEntryDetail {
+	return &ADVEntryDetail{
+		recordType: "6",
+	}
+}
+
+// Parse takes the input record string and parses the ADVEntryDetail values
+// A record shorter than RecordLength is padded with spaces.
+func (ed *ADVEntryDetail) Parse(record string) {
+	record = ed.padRecord(record)
+	// 1-1 Always "6"
+	ed.recordType = "6"
+	// 2-3 ADV transaction code 81 through 88
+	ed.TransactionCode = ed.parseNumField(record[1:3])
+	// 4-11 the RDFI's routing number without the last digit.
+	ed.RDFIIdentification = ed.parseNumField(record[3:11])
+	// 12-12 The last digit of the RDFI's routing number
+	ed.CheckDigit = ed.parseNumField(record[11:12])
+	// 13-27 The receiver's bank account number
+	ed.DFIAccountNumber = strings.TrimSpace(record[12:27])
+	// 28-39 Number of cents of the advice
+	ed.Amount = ed.parseNumField(record[27:39])
+	// 40-48 Routing number of the financial institution the advice is for
+	ed.AdviceRoutingNumber = record[39:48]
+	// 49-53 File identification
+	ed.FileIdentification = strings.TrimSpace(record[48:53])
+	// 54-54 ACH operator data
+	ed.ACHOperatorData = strings.TrimSpace(record[53:54])
+	// 55-76 Name of the receiving financial institution
+	ed.IndividualName = strings.TrimSpace(record[54:76])
+	// 77-78 Discretionary data
+	ed.DiscretionaryData = strings.TrimSpace(record[76:78])
+	// 79-79 Always 0
+	ed.AddendaRecordIndicator = ed.parseNumField(record[78:79])
+	// 80-87 Routing number of the ACH operator
+	ed.ACHOperatorRoutingNumber = record[79:87]
+	// 88-90 Julian day of the year
+	ed.JulianDay = ed.parseNumField(record[87:90])
+	// 91-94 Sequence number
+	ed.SequenceNumber = ed.parseNumField(record[90:94])
+}
+
+// String writes the ADVEntryDetail struct to a 94 character string.
+func (ed *ADVEntryDetail) String() string {
+	return fmt.Sprintf("%v%v%v%v%v%v%v%v%v%v%v%v%v%v%v",
+		ed.recordType,
+		ed.TransactionCode,
+		ed.RDFIIdentificationField(),
+		ed.CheckDigit,
+		ed.DFIAccountNumberField(),
+		ed.AmountField(),
+		ed.alphaField(ed.AdviceRoutingNumber, 9),
+		ed.alphaField(ed.FileIdentification, 5),
+		ed.alphaField(ed.ACHOperatorData, 1),
+		ed.IndividualNameField(),
+		ed.alphaField(ed.DiscretionaryData, 2),
+		ed.AddendaRecordIndicator,
+		ed.alphaField(ed.ACHOperatorRoutingNumber, 8),
+		ed.JulianDayField(),
+		ed.SequenceNumberField())
+}
+
+// Validate performs NACHA format rule checks on the record and returns an error if not Validated
+// The first error encountered is returned and stops that parsing.
+func (ed *ADVEntryDetail) Validate() error {
+	if err := ed.fieldInclusion(); err != nil {
+		return err
+	}
+	if ed.recordType != "6" {
+		msg := fmt.Sprintf(msgRecordType, 6)
+		return &FieldError{FieldName: "recordType", Value: ed.recordType, Msg: msg}
+	}
+	if err := ed.isADVTransactionCode(ed.TransactionCode); err != nil {
+		return &FieldError{FieldName: "TransactionCode", Value: strconv.Itoa(ed.TransactionCode), Msg: err.Error()}
+	}
+	if err := ed.isAlphanumeric(ed.DFIAccountNumber); err != nil {
+		return &FieldError{FieldName: "DFIAccountNumber", Value: ed.DFIAccountNumber, Msg: err.Error()}
+	}
+	if err := ed.isNumeric(ed.AdviceRoutingNumber); err != nil {
+		return &FieldError{FieldName: "AdviceRoutingNumber", Value: ed.AdviceRoutingNumber, Msg: err.Error()}
+	}
+	if err := ed.isAlphanumeric(ed.IndividualName); err != nil {
+		return &FieldError{FieldName: "IndividualName", Value: ed.IndividualName, Msg: err.Error()}
+	}
+	if err := ed.isNumeric(ed.ACHOperatorRoutingNumber); err != nil {
+		return &FieldError{FieldName: "ACHOperatorRoutingNumber", Value: ed.ACHOperatorRoutingNumber, Msg: err.Error()}
+	}
+	calculated := ed.CalculateCheckDigit(ed.RDFIIdentificationField())
+	if calculated != ed.CheckDigit {
+		msg := fmt.Sprintf(msgValidCheckDigit, calculated)
+		return &FieldError{FieldName: "RDFIIdentification", Value: strconv.Itoa(ed.CheckDigit), Msg: msg}
+	}
+	return nil
+}
+
+// fieldInclusion validate mandatory fields are not default values.
+func (ed *ADVEntryDetail) fieldInclusion() error {
+	if ed.recordType == "" {
+		return &FieldError{FieldName: "recordType", Value: ed.recordType, Msg: msgFieldInclusion}
+	}
+	if ed.TransactionCode == 0 {
+		return &FieldError{FieldName: "TransactionCode", Value: strconv.Itoa(ed.TransactionCode), Msg: msgFieldInclusion}
+	}
+	if ed.RDFIIdentification == 0 {
+		return &FieldError{FieldName: "RDFIIdentification", Value: ed.RDFIIdentificationField(), Msg: msgFieldInclusion}
+	}
+	if ed.DFIAccountNumber == "" {
+		return &FieldError{FieldName: "DFIAccountNumber", Value: ed.DFIAccountNumber, Msg: msgFieldInclusion}
+	}
+	if ed.AdviceRoutingNumber == "" {
+		return &FieldError{FieldName: "AdviceRoutingNumber", Value: ed.AdviceRoutingNumber, Msg: msgFieldInclusion}
+	}
+	if ed.IndividualName == "" {
+		return &FieldError{FieldName: "IndividualName", Value: ed.IndividualName, Msg: msgFieldInclusion}
+	}
+	if ed.ACHOperatorRoutingNumber == "" {
+		return &FieldError{FieldName: "ACHOperatorRoutingNumber", Value: ed.ACHOperatorRoutingNumber, Msg: msgFieldInclusion}
+	}
+	return nil
+}
+
+// isADVTransactionCode returns an error if code is not an ADV transaction code
+func (ed *ADVEntryDetail) isADVTransactionCode(code int) error {
+	if code < 81 || code > 88 {
+		return errors.New(msgADVTransactionCode)
+	}
+	return nil
+}
+
+// isCredit returns true if the TransactionCode is a credit advice
+func (ed *ADVEntryDetail) isCredit() bool {
+	return ed.TransactionCode%2 == 1
+}
+
+// SetRDFI takes the 9 digit RDFI routing number and separates it for RDFIIdentification and CheckDigit
+func (ed *ADVEntryDetail) SetRDFI(rdfi int) *ADVEntryDetail {
+	s := ed.numericField(rdfi, 9)
+	ed.RDFIIdentification = ed.parseNumField(s[:8])
+	ed.CheckDigit = ed.parseNumField(s[8:9])
+	return ed
+}
+
+// RDFIIdentificationField get the rdfiIdentification with zero padding
+func (ed *ADVEntryDetail) RDFIIdentificationField() string {
+	return ed.numericField(ed.RDFIIdentification, 8)
+}
+
+// DFIAccountNumberField gets the DFIAccountNumber with space padding
+func (ed *ADVEntryDetail) DFIAccountNumberField() string {
+	return ed.alphaField(ed.DFIAccountNumber, 15)
+}
+
+// AmountField returns a zero padded string of amount
+func (ed *ADVEntryDetail) AmountField() string {
+	return ed.numericField(ed.Amount, 12)
+}
+
+// IndividualNameField returns a space padded string of IndividualName
+func (ed *ADVEntryDetail) IndividualNameField() string {
+	return ed.alphaField(ed.IndividualName, 22)
+}
+
+// JulianDayField returns a zero padded string of JulianDay
+func (ed *ADVEntryDetail) JulianDayField() string {
+	return ed.numericField(ed.JulianDay, 3)
+}
+
+// SequenceNumberField returns a zero padded string of SequenceNumber
+func (ed *ADVEntryDetail) SequenceNumberField() string {
+	return ed.numericField(ed.SequenceNumber, 4)
+}
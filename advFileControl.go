@@ -0,0 +1,161 @@
+// Copyright 2017 The ACH Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package ach
+
+import "fmt"
+
+// ADVFileControl record contains entry counts, dollar totals and hash totals
+// accumulated from each ADV batch control record in the file. ADV file controls
+// carry 20 digit dollar totals.
+type ADVFileControl struct {
+	// RecordType defines the type of record in the block. fileControlPos 9
+	recordType string
+	// BatchCount total number of batches (i.e., ‘5’ records) in the file
+	BatchCount int
+	// BlockCount total number of records in the file divided by 10
+	BlockCount int
+	// EntryAddendaCount total ADV entry detail records in the file
+	EntryAddendaCount int
+	// EntryHash calculated in the same manner as the batch hash total but includes total from entire file
+	EntryHash int
+	// TotalDebitEntryDollarAmountInFile contains accumulated Batch debit totals within the file.
+	TotalDebitEntryDollarAmountInFile int
+	// TotalCreditEntryDollarAmountInFile contains accumulated Batch credit totals within the file.
+	TotalCreditEntryDollarAmountInFile int
+	// Reserved should be blank.
+	reserved string
+	// validator is composed for data validation
+	validator
+	// converters is composed for ACH to golang Converters
+	converters
+}
+
+// NewADVFileControl returns a new ADVFileControl with default values for none exported fields
+func NewADVFileControl() ADVFileControl {
+	return ADVFileControl{
+		recordType: "9",
+		reserved:   "                       ",
+	}
+}
+
+// Parse takes the input record string and parses the ADVFileControl values
+// A record shorter than RecordLength is padded with spaces.
+func (fc *ADVFileControl) Parse(record string) {
+	record = fc.padRecord(record)
+	// 1-1 Always "9"
+	fc.recordType = "9"
+	// 2-7 The total number of Batch Header Record in the file
+	fc.BatchCount = fc.parseNumField(record[1:7])
+	// 8-13 The total number of blocks on the file
+	fc.BlockCount = fc.parseNumField(record[7:13])
+	// 14-21 Total number of ADV Entry Detail Records in the file
+	fc.EntryAddendaCount = fc.parseNumField(record[13:21])
+	// 22-31 Total of all positions 4-11 on each ADV Entry Detail Record in the file
+	fc.EntryHash = fc.parseNumField(record[21:31])
+	// 32-51 Number of cents of debit entries within the file
+	fc.TotalDebitEntryDollarAmountInFile = fc.parseNumField(record[31:51])
+	// 52-71 Number of cents of credit entries within the file
+	fc.TotalCreditEntryDollarAmountInFile = fc.parseNumField(record[51:71])
+	// 72-94 Reserved Always blank (just fill with spaces)
+	fc.reserved = "                       "
+}
+
+// String writes the ADVFileControl struct to a 94 character string.
+func (fc *ADVFileControl) String() string {
+	return fmt.Sprintf("%v%v%v%v%v%v%v%v",
+		fc.recordType,
+		fc.BatchCountField(),
+		fc.BlockCountField(),
+		fc.EntryAddendaCountField(),
+		fc.EntryHashField(),
+		fc.TotalDebitEntryDollarAmountInFileField(),
+		fc.TotalCreditEntryDollarAmountInFileField(),
+		fc.alphaField(fc.reserved, 23),
+	)
+}
+
+// Validate performs NACHA format rule checks on the record and returns an error if not Validated
+// The first error encountered is returned and stops that parsing.
+func (fc *ADVFileControl) Validate() error {
+	if err := fc.fieldInclusion(); err != nil {
+		return err
+	}
+	if fc.recordType != "9" {
+		msg := fmt.Sprintf(msgRecordType, 9)
+		return &FieldError{FieldName: "recordType", Value: fc.recordType, Msg: msg}
+	}
+	return nil
+}
+
+// fieldInclusion validate mandatory fields are not default values.
+func (fc *ADVFileControl) fieldInclusion() error {
+	if fc.recordType == "" {
+		return &FieldError{FieldName: "recordType", Value: fc.recordType, Msg: msgFieldInclusion}
+	}
+	if fc.BatchCount == 0 {
+		return &FieldError{FieldName: "BatchCount", Value: fc.BatchCountField(), Msg: msgFieldInclusion}
+	}
+	if fc.BlockCount == 0 {
+		return &FieldError{FieldName: "BlockCount", Value: fc.BlockCountField(), Msg: msgFieldInclusion}
+	}
+	if fc.EntryAddendaCount == 0 {
+		return &FieldError{FieldName: "EntryAddendaCount", Value: fc.EntryAddendaCountField(), Msg: msgFieldInclusion}
+	}
+	return nil
+}
+
+// BatchCountField gets a string of the batch count zero padded
+func (fc *ADVFileControl) BatchCountField() string {
+	return fc.numericField(fc.BatchCount, 6)
+}
+
+// BlockCountField gets a string of the block count zero padded
+func (fc *ADVFileControl) BlockCountField() string {
+	return fc.numericField(fc.BlockCount, 6)
+}
+
+// EntryAddendaCountField gets a string of entry count zero padded
+func (fc *ADVFileControl) EntryAddendaCountField() string {
+	return fc.numericField(fc.EntryAddendaCount, 8)
+}
+
+// EntryHashField gets a string of entry hash zero padded
+func (fc *ADVFileControl) EntryHashField() string {
+	return fc.numericField(fc.EntryHash, 10)
+}
+
+// TotalDebitEntryDollarAmountInFileField get a zero padded Total debit Entry Amount
+func (fc *ADVFileControl) TotalDebitEntryDollarAmountInFileField() string {
+	return fc.numericField(fc.TotalDebitEntryDollarAmountInFile, 20)
+}
+
+// TotalCreditEntryDollarAmountInFileField get a zero padded Total credit Entry Amount
+func (fc *ADVFileControl) TotalCreditEntryDollarAmountInFileField() string {
+	return fc.numericField(fc.TotalCreditEntryDollarAmountInFile, 20)
+}
+
+// advFileControl returns the ADV File Control record with the totals of fc
+func advFileControl(fc FileControl) ADVFileControl {
+	advControl := NewADVFileControl()
+	advControl.BatchCount = fc.BatchCount
+	advControl.BlockCount = fc.BlockCount
+	advControl.EntryAddendaCount = fc.EntryAddendaCount
+	advControl.EntryHash = fc.EntryHash
+	advControl.TotalDebitEntryDollarAmountInFile = fc.TotalDebitEntryDollarAmountInFile
+	advControl.TotalCreditEntryDollarAmountInFile = fc.TotalCreditEntryDollarAmountInFile
+	return advControl
+}
+
+// fileControl returns the File Control with the totals of the ADV File Control
+func (fc *ADVFileControl) fileControl() FileControl {
+	control := NewFileControl()
+	control.BatchCount = fc.BatchCount
+	control.BlockCount = fc.BlockCount
+	control.EntryAddendaCount = fc.EntryAddendaCount
+	control.EntryHash = fc.EntryHash
+	control.TotalDebitEntryDollarAmountInFile = fc.TotalDebitEntryDollarAmountInFile
+	control.TotalCreditEntryDollarAmountInFile = fc.TotalCreditEntryDollarAmountInFile
+	return control
+}
@@ -3,6 +3,7 @@ package ach
 import (
 	"fmt"
 	"strconv"
+	"time"
 )
 
 // Batch holds the Batch Header and Batch Control and all Entry Records for PPD Entries
@@ -10,10 +11,36 @@ type batch struct {
 	header  *BatchHeader
 	entries []*EntryDetail
 	control *BatchControl
+	// batchOptions are the optional checks set on the batch
+	batchOptions
 	// Converters is composed for ACH to GoLang Converters
 	converters
 }
 
+// batchOptions are the optional checks of a batch. They are set with the Set methods of the
+// concrete batch types and are copied by cloneBatch.
+type batchOptions struct {
+	// identificationNumberValidator replaces the default IdentificationNumber check when set
+	identificationNumberValidator func(string) error
+	// requireUniformCategory rejects batches with both forward and return entries
+	requireUniformCategory bool
+	// allowZeroAmountPrenotes accepts zero amount prenotes and rejects other zero amount entries
+	allowZeroAmountPrenotes bool
+	// requireSegregatedPrenotes rejects batches with both prenote and live entries
+	requireSegregatedPrenotes bool
+}
+
+// batchOptioner is implemented by the batch types with batchOptions so the options can be read
+// and set through a Batcher.
+type batchOptioner interface {
+	options() *batchOptions
+}
+
+// options returns the optional checks of the batch so they can be copied
+func (batch *batch) options() *batchOptions {
+	return &batch.batchOptions
+}
+
 // NewBatch takes a BatchParm and returns a matching SEC code batch type that is a batcher. Returns and error if the SEC code is not supported.
 func NewBatch(bp BatchParam) (Batcher, error) {
 	switch sec := bp.StandardEntryClass; sec {
@@ -25,24 +52,88 @@ func NewBatch(bp BatchParam) (Batcher, error) {
 		return NewBatchCCD(bp), nil
 	case "COR":
 		return NewBatchCOR(bp), nil
+	case "CTX":
+		return NewBatchCTX(bp), nil
+	case "ENR":
+		return NewBatchENR(bp), nil
+	case "TRX":
+		return NewBatchTRX(bp), nil
+	case "POP":
+		return NewBatchPOP(bp), nil
+	case "ADV":
+		return NewBatchADV(bp), nil
 	default:
 		msg := fmt.Sprintf(msgFileNoneSEC, sec)
 		return nil, &FileError{FieldName: "StandardEntryClassCode", Msg: msg}
 	}
 }
 
+// cloneBatch returns a copy of batch with copies of its header, control, entries, addenda and options
+func cloneBatch(batch Batcher) (Batcher, error) {
+	clone, err := newBatchFrom(batch)
+	if err != nil {
+		return nil, err
+	}
+	for _, entry := range batch.GetEntries() {
+		e := *entry
+		e.Addendum = append([]Addenda(nil), entry.Addendum...)
+		e.ReturnAddendum = append([]ReturnAddenda(nil), entry.ReturnAddendum...)
+		if entry.Addenda98 != nil {
+			addenda98 := *entry.Addenda98
+			e.Addenda98 = &addenda98
+		}
+		clone.AddEntry(&e)
+	}
+	if adv, ok := batch.(*BatchADV); ok {
+		advClone := clone.(*BatchADV)
+		for _, entry := range adv.GetADVEntries() {
+			e := *entry
+			advClone.AddADVEntry(&e)
+		}
+	}
+	return clone, nil
+}
+
+// newBatchFrom returns a batch without entries with copies of the header, control, ADV control
+// and options of batch
+func newBatchFrom(batch Batcher) (Batcher, error) {
+	header := *batch.GetHeader()
+	clone, err := NewBatch(BatchParam{StandardEntryClass: header.StandardEntryClassCode})
+	if err != nil {
+		return nil, err
+	}
+	clone.SetHeader(&header)
+	control := *batch.GetControl()
+	clone.SetControl(&control)
+	if src, ok := batch.(batchOptioner); ok {
+		*clone.(batchOptioner).options() = *src.options()
+	}
+	if adv, ok := batch.(*BatchADV); ok {
+		advControl := *adv.GetADVControl()
+		clone.(*BatchADV).SetADVControl(&advControl)
+	}
+	return clone, nil
+}
+
 // verify checks basic valid NACHA batch rules. Assumes properly parsed records. This does not mean it is a valid batch as validity is tied to each batch type
 func (batch *batch) verify() error {
 	batchNumber := batch.header.BatchNumber
 
 	// verify field inclusion in all the records of the batch.
 	if err := batch.isFieldInclusion(); err != nil {
+		if e, ok := err.(*BatchError); ok {
+			return e
+		}
 		// convert the field error in to a batch error for a consistent api
 		if e, ok := err.(*FieldError); ok {
 			return &BatchError{BatchNumber: batchNumber, FieldName: e.FieldName, Msg: e.Msg}
 		}
 		return &BatchError{BatchNumber: batchNumber, FieldName: "FieldError", Msg: err.Error()}
 	}
+	// forward batches must have an effective entry date, return batches may leave it blank
+	if _, err := batch.EffectiveEntryDate(); err != nil {
+		return err
+	}
 	// validate batch header and control codes are the same
 	if batch.header.ServiceClassCode != batch.control.ServiceClassCode {
 		msg := fmt.Sprintf(msgBatchHeaderControlEquality, batch.header.ServiceClassCode, batch.control.ServiceClassCode)
@@ -91,6 +182,28 @@ func (batch *batch) verify() error {
 	if err := batch.isAddendaSequence(); err != nil {
 		return err
 	}
+
+	if err := batch.isIdentificationNumber(); err != nil {
+		return err
+	}
+
+	if batch.requireUniformCategory {
+		if err := batch.isCategoryUniform(); err != nil {
+			return err
+		}
+	}
+
+	if batch.allowZeroAmountPrenotes {
+		if err := batch.isZeroAmountPrenote(); err != nil {
+			return err
+		}
+	}
+
+	if batch.requireSegregatedPrenotes {
+		if err := batch.isPrenoteSegregated(); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
@@ -108,7 +221,10 @@ func (batch *batch) build() error {
 	entryCount := 0
 	seq := 1
 	for i, entry := range batch.entries {
-		entryCount = entryCount + 1 + len(entry.Addendum)
+		entryCount = entryCount + 1 + len(entry.Addendum) + len(entry.ReturnAddendum)
+		if entry.Addenda98 != nil {
+			entryCount++
+		}
 		// Allows for manual override of trace numbers if current entry's trace number is already set before
 		// the batch is built.
 		currentTraceNumberODFI, err := strconv.Atoi(entry.TraceNumberField()[:8])
@@ -125,6 +241,12 @@ func (batch *batch) build() error {
 			batch.entries[i].Addendum[x].EntryDetailSequenceNumber = batch.parseNumField(batch.entries[i].TraceNumberField()[8:])
 			addendaSeq++
 		}
+		for x := range entry.ReturnAddendum {
+			batch.entries[i].ReturnAddendum[x].Trace = batch.entries[i].TraceNumber
+		}
+		if entry.Addenda98 != nil {
+			entry.Addenda98.Trace = entry.TraceNumber
+		}
 	}
 
 	// build a BatchControl record
@@ -161,28 +283,106 @@ func (batch *batch) GetControl() *BatchControl {
 	return batch.control
 }
 
+// EffectiveEntryDate returns the date on which the entries in the batch are to settle.
+// Entry Detail Records do not carry an effective date of their own so the Batch Header
+// date applies to every entry. Entries intended to settle on different dates must be
+// placed in separate batches. An error is returned if the batch header date is not set
+// on a forward batch. Return and notification of change batches may have a blank date.
+func (batch *batch) EffectiveEntryDate() (time.Time, error) {
+	if batch.header == nil {
+		return time.Time{}, &BatchError{BatchNumber: batch.batchNumber(), FieldName: "EffectiveEntryDate", Msg: msgBatchEffectiveEntryDate}
+	}
+	if batch.header.EffectiveEntryDate.IsZero() && !batch.isReturn() {
+		return time.Time{}, &BatchError{BatchNumber: batch.batchNumber(), FieldName: "EffectiveEntryDate", Msg: msgBatchEffectiveEntryDate}
+	}
+	return batch.header.EffectiveEntryDate, nil
+}
+
 // GetEntries returns a slice of entry details for the batch
 func (batch *batch) GetEntries() []*EntryDetail {
 	return batch.entries
 }
 
+// SetIdentificationNumberValidator sets fn to check the IdentificationNumber of every entry
+// when the batch is validated. It replaces the default check that IdentificationNumber
+// fits in its 15 character field. Passing nil restores the default check.
+func (batch *batch) SetIdentificationNumberValidator(fn func(string) error) {
+	batch.identificationNumberValidator = fn
+}
+
+// SetRequireUniformCategory sets the batch to reject a mix of entries with Category
+// CategoryForward and CategoryReturn when it is validated. An empty Category is a forward entry.
+// Forward and return entries are not mixed in the same batch.
+func (batch *batch) SetRequireUniformCategory(require bool) {
+	batch.requireUniformCategory = require
+}
+
+// SetAllowZeroAmountPrenotes sets the batch to accept prenote entries with a zero Amount in any
+// batch, which are otherwise rejected unless the OriginatorStatusCode is 2, and to reject zero
+// Amount entries that are not prenotes.
+func (batch *batch) SetAllowZeroAmountPrenotes(allow bool) {
+	batch.allowZeroAmountPrenotes = allow
+}
+
+// SetRequireSegregatedPrenotes sets the batch to reject a mix of prenote and live entries when it
+// is validated.
+func (batch *batch) SetRequireSegregatedPrenotes(require bool) {
+	batch.requireSegregatedPrenotes = require
+}
+
 // AddEntry appends an EntryDetail to the Batch
 func (batch *batch) AddEntry(entry *EntryDetail) {
 	batch.entries = append(batch.entries, entry)
 }
 
+// isReturn returns true for notification of change batches and batches where every entry
+// has return addenda.
+func (batch *batch) isReturn() bool {
+	if batch.header != nil && batch.header.StandardEntryClassCode == cor {
+		return true
+	}
+	if len(batch.entries) == 0 {
+		return false
+	}
+	for _, entry := range batch.entries {
+		if !entry.HasReturnAddenda() {
+			return false
+		}
+	}
+	return true
+}
+
+// batchNumber returns the header batch number or zero if the batch has no header
+func (batch *batch) batchNumber() int {
+	if batch.header == nil {
+		return 0
+	}
+	return batch.header.BatchNumber
+}
+
+// entryError returns a BatchError for the field of the entry at index i of the batch
+func (batch *batch) entryError(i int, fieldName, msg string) *BatchError {
+	return &BatchError{BatchNumber: batch.header.BatchNumber, FieldName: fieldName, Msg: msg, entryIndex: i + 1}
+}
+
 // isFieldInclusion iterates through all the records in the batch and verifies against default fields
 func (batch *batch) isFieldInclusion() error {
 	if err := batch.header.Validate(); err != nil {
 		return err
 	}
-	for _, entry := range batch.entries {
+	for i, entry := range batch.entries {
 		if err := entry.Validate(); err != nil {
+			if e, ok := err.(*FieldError); ok {
+				return batch.entryError(i, e.FieldName, e.Msg)
+			}
 			return err
 		}
 		for _, addenda := range entry.Addendum {
 			if err := addenda.Validate(); err != nil {
-				return nil
+				if e, ok := err.(*FieldError); ok {
+					return batch.entryError(i, e.FieldName, e.Msg)
+				}
+				return err
 			}
 		}
 	}
@@ -192,14 +392,43 @@ func (batch *batch) isFieldInclusion() error {
 	return nil
 }
 
+// controlErrors returns every error found validating the batch control and comparing it with
+// the batch header and entries instead of stopping at the first one.
+func (batch *batch) controlErrors() []error {
+	var errs []error
+	if err := batch.control.Validate(); err != nil {
+		errs = append(errs, err)
+	}
+	batchNumber := batch.header.BatchNumber
+	if batch.header.ServiceClassCode != batch.control.ServiceClassCode {
+		msg := fmt.Sprintf(msgBatchHeaderControlEquality, batch.header.ServiceClassCode, batch.control.ServiceClassCode)
+		errs = append(errs, &BatchError{BatchNumber: batchNumber, FieldName: "ServiceClassCode", Msg: msg})
+	}
+	if batch.header.CompanyIdentification != batch.control.CompanyIdentification {
+		msg := fmt.Sprintf(msgBatchHeaderControlEquality, batch.header.CompanyIdentification, batch.control.CompanyIdentification)
+		errs = append(errs, &BatchError{BatchNumber: batchNumber, FieldName: "CompanyIdentification", Msg: msg})
+	}
+	if batch.header.ODFIIdentification != batch.control.ODFIIdentification {
+		msg := fmt.Sprintf(msgBatchHeaderControlEquality, batch.header.ODFIIdentification, batch.control.ODFIIdentification)
+		errs = append(errs, &BatchError{BatchNumber: batchNumber, FieldName: "ODFIIdentification", Msg: msg})
+	}
+	if batch.header.BatchNumber != batch.control.BatchNumber {
+		msg := fmt.Sprintf(msgBatchHeaderControlEquality, batch.header.BatchNumber, batch.control.BatchNumber)
+		errs = append(errs, &BatchError{BatchNumber: batchNumber, FieldName: "BatchNumber", Msg: msg})
+	}
+	for _, check := range []func() error{batch.isBatchEntryCount, batch.isBatchAmount, batch.isEntryHash} {
+		if err := check(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errs
+}
+
 // isBatchEntryCount validate Entry count is accurate
 // The Entry/Addenda Count Field is a tally of each Entry Detail and Addenda
 // Record processed within the batch
 func (batch *batch) isBatchEntryCount() error {
-	entryCount := 0
-	for _, entry := range batch.entries {
-		entryCount = entryCount + 1 + len(entry.Addendum) + len(entry.ReturnAddendum)
-	}
+	entryCount := batch.EntryAddendaCount()
 	if entryCount != batch.control.EntryAddendaCount {
 		msg := fmt.Sprintf(msgBatchCalculatedControlEquality, entryCount, batch.control.EntryAddendaCount)
 		return &BatchError{BatchNumber: batch.header.BatchNumber, FieldName: "EntryAddendaCount", Msg: msg}
@@ -224,6 +453,26 @@ func (batch *batch) isBatchAmount() error {
 	return nil
 }
 
+// EntryAddendaCount returns the number of entry and addenda records in the batch, which is the
+// EntryAddendaCount the batch control should carry. The batch control is not used or modified.
+func (batch *batch) EntryAddendaCount() int {
+	entryCount := 0
+	for _, entry := range batch.entries {
+		entryCount = entryCount + 1 + len(entry.Addendum) + len(entry.ReturnAddendum)
+		if entry.Addenda98 != nil {
+			entryCount++
+		}
+	}
+	return entryCount
+}
+
+// NetAmount returns the total credit amount minus the total debit amount of the entries in
+// the batch. A negative result is a net debit. The batch control is not used or modified.
+func (batch *batch) NetAmount() int {
+	credit, debit := batch.calculateBatchAmounts()
+	return credit - debit
+}
+
 func (batch *batch) calculateBatchAmounts() (credit int, debit int) {
 	for _, entry := range batch.entries {
 		if entry.TransactionCode == 21 || entry.TransactionCode == 22 || entry.TransactionCode == 23 || entry.TransactionCode == 31 || entry.TransactionCode == 32 || entry.TransactionCode == 33 {
@@ -240,10 +489,10 @@ func (batch *batch) calculateBatchAmounts() (credit int, debit int) {
 // be in ascending Trace Number order (although Trace Numbers need not necessarily be consecutive).
 func (batch *batch) isSequenceAscending() error {
 	lastSeq := -1
-	for _, entry := range batch.entries {
+	for i, entry := range batch.entries {
 		if entry.TraceNumber <= lastSeq {
 			msg := fmt.Sprintf(msgBatchAscending, entry.TraceNumber, lastSeq)
-			return &BatchError{BatchNumber: batch.header.BatchNumber, FieldName: "TraceNumber", Msg: msg}
+			return batch.entryError(i, "TraceNumber", msg)
 		}
 		lastSeq = entry.TraceNumber
 	}
@@ -273,10 +522,13 @@ func (batch *batch) calculateEntryHash() string {
 // The Originator Status Code is not equal to “2” for DNE if the Transaction Code is 23 or 33
 func (batch *batch) isOriginatorDNE() error {
 	if batch.header.OriginatorStatusCode != 2 {
-		for _, entry := range batch.entries {
+		for i, entry := range batch.entries {
+			if batch.allowZeroAmountPrenotes && entry.isPrenote() && entry.Amount == 0 {
+				continue
+			}
 			if entry.TransactionCode == 23 || entry.TransactionCode == 33 {
 				msg := fmt.Sprintf(msgBatchOriginatorDNE, batch.header.OriginatorStatusCode)
-				return &BatchError{BatchNumber: batch.header.BatchNumber, FieldName: "OriginatorStatusCode", Msg: msg}
+				return batch.entryError(i, "OriginatorStatusCode", msg)
 			}
 		}
 	}
@@ -286,10 +538,10 @@ func (batch *batch) isOriginatorDNE() error {
 // isTraceNumberODFI checks if the first 8 positions of the entry detail trace number
 // match the batch header odfi
 func (batch *batch) isTraceNumberODFI() error {
-	for _, entry := range batch.entries {
+	for i, entry := range batch.entries {
 		if batch.header.ODFIIdentificationField() != entry.TraceNumberField()[:8] {
 			msg := fmt.Sprintf(msgBatchTraceNumberNotODFI, batch.header.ODFIIdentificationField(), entry.TraceNumberField()[:8])
-			return &BatchError{BatchNumber: batch.header.BatchNumber, FieldName: "ODFIIdentificationField", Msg: msg}
+			return batch.entryError(i, "ODFIIdentificationField", msg)
 		}
 	}
 
@@ -298,24 +550,24 @@ func (batch *batch) isTraceNumberODFI() error {
 
 // isAddendaSequence check multiple errors on addenda records in the batch entries
 func (batch *batch) isAddendaSequence() error {
-	for _, entry := range batch.entries {
+	for i, entry := range batch.entries {
 		if len(entry.Addendum) > 0 {
 			// addenda without indicator flag of 1
-			if entry.AddendaRecordIndicator != 1 {
-				return &BatchError{BatchNumber: batch.header.BatchNumber, FieldName: "AddendaRecordIndicator", Msg: msgBatchAddendaIndicator}
+			if !entry.HasAddenda() {
+				return batch.entryError(i, "AddendaRecordIndicator", msgBatchAddendaIndicator)
 			}
 			lastSeq := -1
 			// check if sequence is assending
 			for _, addenda := range entry.Addendum {
 				if addenda.SequenceNumber < lastSeq {
 					msg := fmt.Sprintf(msgBatchAscending, addenda.SequenceNumber, lastSeq)
-					return &BatchError{BatchNumber: batch.header.BatchNumber, FieldName: "SequenceNumber", Msg: msg}
+					return batch.entryError(i, "SequenceNumber", msg)
 				}
 				lastSeq = addenda.SequenceNumber
 				// check that we are in the correct Entry Detail
 				if !(addenda.EntryDetailSequenceNumberField() == entry.TraceNumberField()[8:]) {
 					msg := fmt.Sprintf(msgBatchAddendaTraceNumber, addenda.EntryDetailSequenceNumberField(), entry.TraceNumberField()[8:])
-					return &BatchError{BatchNumber: batch.header.BatchNumber, FieldName: "TraceNumber", Msg: msg}
+					return batch.entryError(i, "TraceNumber", msg)
 				}
 			}
 		}
@@ -323,20 +575,109 @@ func (batch *batch) isAddendaSequence() error {
 	return nil
 }
 
+// isEntrySEC checks each entry against the field requirements of the batch SEC code. Batch types
+// call it after checking the SEC code of the header.
+func (batch *batch) isEntrySEC() error {
+	for i, entry := range batch.entries {
+		if err := entry.ValidateForSEC(batch.header.StandardEntryClassCode); err != nil {
+			if e, ok := err.(*FieldError); ok {
+				return batch.entryError(i, e.FieldName, e.Msg)
+			}
+			return err
+		}
+	}
+	return nil
+}
+
+// isZeroAmountPrenote checks that only prenote entries have a zero Amount. The zero dollar
+// remittance transaction codes and SEC codes such as ENR and COR that are never for an
+// amount are not checked.
+func (batch *batch) isZeroAmountPrenote() error {
+	sec := batch.header.StandardEntryClassCode
+	if sec == cor || entrySECRules[sec].zeroAmount {
+		return nil
+	}
+	for i, entry := range batch.entries {
+		if entry.Amount != 0 || entry.isPrenote() {
+			continue
+		}
+		switch entry.TransactionCode {
+		case 24, 29, 34, 39:
+			continue
+		}
+		msg := fmt.Sprintf(msgBatchZeroAmount, entry.TransactionCode)
+		return batch.entryError(i, "Amount", msg)
+	}
+	return nil
+}
+
+// isCategoryUniform checks that the batch does not have both forward and return entries
+func (batch *batch) isCategoryUniform() error {
+	var forward, ret *EntryDetail
+	for i, entry := range batch.entries {
+		if entry.Category == CategoryReturn {
+			ret = entry
+		} else {
+			forward = entry
+		}
+		if forward != nil && ret != nil {
+			msg := fmt.Sprintf(msgBatchCategoryMixed, forward.TraceNumberField(), ret.TraceNumberField())
+			return batch.entryError(i, "Category", msg)
+		}
+	}
+	return nil
+}
+
+// isPrenoteSegregated checks that the batch does not have both prenote and live entries
+func (batch *batch) isPrenoteSegregated() error {
+	var prenote, live *EntryDetail
+	for i, entry := range batch.entries {
+		if entry.isPrenote() {
+			prenote = entry
+		} else {
+			live = entry
+		}
+		if prenote != nil && live != nil {
+			msg := fmt.Sprintf(msgBatchPrenoteMixed, prenote.TransactionCode, live.TransactionCode)
+			return batch.entryError(i, "TransactionCode", msg)
+		}
+	}
+	return nil
+}
+
+// isIdentificationNumber checks the IdentificationNumber of each entry with the batch
+// identificationNumberValidator or, if it is not set, that it is no longer than 15 characters.
+func (batch *batch) isIdentificationNumber() error {
+	for i, entry := range batch.entries {
+		if batch.identificationNumberValidator != nil {
+			if err := batch.identificationNumberValidator(entry.IdentificationNumber); err != nil {
+				msg := fmt.Sprintf("%v %v", entry.IdentificationNumber, err)
+				return batch.entryError(i, "IdentificationNumber", msg)
+			}
+			continue
+		}
+		if len(entry.IdentificationNumber) > 15 {
+			msg := fmt.Sprintf(msgBatchFieldLength, entry.IdentificationNumber, 15)
+			return batch.entryError(i, "IdentificationNumber", msg)
+		}
+	}
+	return nil
+}
+
 // isAddendaCount iterates through each entry detail and checks the number of addendum is greater than the count paramater otherwise it returns an error.
 // Following SEC codes allow for none or one Addendum
 // "PPD", "WEB", "CCD", "CIE", "DNE", "MTE", "POS", "SHR"
 func (batch *batch) isAddendaCount(count int) error {
-	for _, entry := range batch.entries {
+	for i, entry := range batch.entries {
 		if !entry.HasReturnAddenda() {
 			if len(entry.Addendum) > count {
 				msg := fmt.Sprintf(msgBatchAddendaCount, len(entry.Addendum), count, batch.header.StandardEntryClassCode)
-				return &BatchError{BatchNumber: batch.header.BatchNumber, FieldName: "AddendaCount", Msg: msg}
+				return batch.entryError(i, "AddendaCount", msg)
 			}
 		} else {
 			if len(entry.ReturnAddendum) > count {
 				msg := fmt.Sprintf(msgBatchAddendaCount, len(entry.ReturnAddendum), count, batch.header.StandardEntryClassCode)
-				return &BatchError{BatchNumber: batch.header.BatchNumber, FieldName: "ReturnAddendaCount", Msg: msg}
+				return batch.entryError(i, "ReturnAddendaCount", msg)
 			}
 		}
 	}
@@ -345,11 +686,11 @@ func (batch *batch) isAddendaCount(count int) error {
 
 // isTypeCode takes a typecode string and verifies addenda records match
 func (batch *batch) isTypeCode(typeCode string) error {
-	for _, entry := range batch.entries {
+	for i, entry := range batch.entries {
 		for _, addenda := range entry.Addendum {
 			if addenda.TypeCode != typeCode {
 				msg := fmt.Sprintf(msgBatchTypeCode, addenda.TypeCode, typeCode, batch.header.StandardEntryClassCode)
-				return &BatchError{BatchNumber: batch.header.BatchNumber, FieldName: "TypeCode", Msg: msg}
+				return batch.entryError(i, "TypeCode", msg)
 			}
 		}
 	}
@@ -0,0 +1,232 @@
+// Copyright 2017 The ACH Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package ach
+
+import "fmt"
+
+// BatchADV holds the Batch Header, ADV Entry Detail Records and ADV Batch Control of an
+// Automated Accounting Advice batch. ADV batches are created by an ACH operator to report
+// ACH activity to a financial institution and use service class code 280 or 200.
+//
+// The BatchControl returned by GetControl mirrors the totals of the ADV Batch Control so
+// the batch can be added to a File like any other batch.
+type BatchADV struct {
+	batch
+	advEntries []*ADVEntryDetail
+	advControl *ADVBatchControl
+}
+
+var (
+	msgBatchADVServiceClass = "%v is not service class code 280 or 200 for automated accounting advices"
+	msgBatchADVEntry        = "ADV batches only contain ADV entry detail records"
+)
+
+// NewBatchADV returns a *BatchADV
+func NewBatchADV(params ...BatchParam) *BatchADV {
+	batch := new(BatchADV)
+	batch.SetControl(NewBatchControl())
+	batch.SetADVControl(NewADVBatchControl())
+
+	if len(params) > 0 {
+		bh := NewBatchHeader(params[0])
+		bh.StandardEntryClassCode = "ADV"
+		bh.ServiceClassCode = 280
+		batch.SetHeader(bh)
+		return batch
+	}
+	bh := NewBatchHeader()
+	bh.StandardEntryClassCode = "ADV"
+	bh.ServiceClassCode = 280
+	batch.SetHeader(bh)
+	return batch
+}
+
+// AddADVEntry appends an ADVEntryDetail to the batch
+func (batch *BatchADV) AddADVEntry(entry *ADVEntryDetail) {
+	batch.advEntries = append(batch.advEntries, entry)
+}
+
+// GetADVEntries returns the ADV entry details of the batch
+func (batch *BatchADV) GetADVEntries() []*ADVEntryDetail {
+	return batch.advEntries
+}
+
+// SetADVControl sets the ADVBatchControl of the batch and mirrors it in the BatchControl
+func (batch *BatchADV) SetADVControl(advControl *ADVBatchControl) {
+	batch.advControl = advControl
+	bc := NewBatchControl()
+	bc.ServiceClassCode = advControl.ServiceClassCode
+	bc.EntryAddendaCount = advControl.EntryAddendaCount
+	bc.EntryHash = advControl.EntryHash
+	bc.TotalDebitEntryDollarAmount = advControl.TotalDebitEntryDollarAmount
+	bc.TotalCreditEntryDollarAmount = advControl.TotalCreditEntryDollarAmount
+	bc.ODFIIdentification = advControl.ODFIIdentification
+	bc.BatchNumber = advControl.BatchNumber
+	batch.SetControl(bc)
+}
+
+// GetADVControl returns the ADVBatchControl of the batch. The BatchNumber is kept in step
+// with the BatchControl, which File.Create renumbers.
+func (batch *BatchADV) GetADVControl() *ADVBatchControl {
+	batch.advControl.BatchNumber = batch.control.BatchNumber
+	return batch.advControl
+}
+
+// Validate ensures the batch meets NACHA rules specific to this batch type.
+func (batch *BatchADV) Validate() error {
+	batchNumber := batch.header.BatchNumber
+	if err := batch.header.Validate(); err != nil {
+		return batch.fieldError(err)
+	}
+	if batch.header.StandardEntryClassCode != "ADV" {
+		msg := fmt.Sprintf(msgBatchSECType, batch.header.StandardEntryClassCode, "ADV")
+		return &BatchError{BatchNumber: batchNumber, FieldName: "StandardEntryClassCode", Msg: msg}
+	}
+	if batch.header.ServiceClassCode != 280 && batch.header.ServiceClassCode != 200 {
+		msg := fmt.Sprintf(msgBatchADVServiceClass, batch.header.ServiceClassCode)
+		return &BatchError{BatchNumber: batchNumber, FieldName: "ServiceClassCode", Msg: msg}
+	}
+	if len(batch.entries) > 0 {
+		return &BatchError{BatchNumber: batchNumber, FieldName: "entries", Msg: msgBatchADVEntry}
+	}
+	for _, entry := range batch.advEntries {
+		if err := entry.Validate(); err != nil {
+			return batch.fieldError(err)
+		}
+	}
+	advControl := batch.GetADVControl()
+	if err := advControl.Validate(); err != nil {
+		return batch.fieldError(err)
+	}
+	if batch.header.ODFIIdentification != advControl.ODFIIdentification {
+		msg := fmt.Sprintf(msgBatchHeaderControlEquality, batch.header.ODFIIdentification, advControl.ODFIIdentification)
+		return &BatchError{BatchNumber: batchNumber, FieldName: "ODFIIdentification", Msg: msg}
+	}
+	if batch.header.BatchNumber != advControl.BatchNumber {
+		msg := fmt.Sprintf(msgBatchHeaderControlEquality, batch.header.BatchNumber, advControl.BatchNumber)
+		return &BatchError{BatchNumber: batchNumber, FieldName: "BatchNumber", Msg: msg}
+	}
+	if batch.EntryAddendaCount() != advControl.EntryAddendaCount {
+		msg := fmt.Sprintf(msgBatchCalculatedControlEquality, batch.EntryAddendaCount(), advControl.EntryAddendaCount)
+		return &BatchError{BatchNumber: batchNumber, FieldName: "EntryAddendaCount", Msg: msg}
+	}
+	credit, debit := batch.calculateADVAmounts()
+	if debit != advControl.TotalDebitEntryDollarAmount {
+		msg := fmt.Sprintf(msgBatchCalculatedControlEquality, debit, advControl.TotalDebitEntryDollarAmount)
+		return &BatchError{BatchNumber: batchNumber, FieldName: "TotalDebitEntryDollarAmount", Msg: msg}
+	}
+	if credit != advControl.TotalCreditEntryDollarAmount {
+		msg := fmt.Sprintf(msgBatchCalculatedControlEquality, credit, advControl.TotalCreditEntryDollarAmount)
+		return &BatchError{BatchNumber: batchNumber, FieldName: "TotalCreditEntryDollarAmount", Msg: msg}
+	}
+	if hash := batch.calculateADVEntryHash(); hash != advControl.EntryHashField() {
+		msg := fmt.Sprintf(msgBatchCalculatedControlEquality, hash, advControl.EntryHashField())
+		return &BatchError{BatchNumber: batchNumber, FieldName: "EntryHash", Msg: msg}
+	}
+	return nil
+}
+
+// controlErrors returns every error found validating the ADV batch control and comparing it
+// with the batch header and ADV entries instead of stopping at the first one.
+func (batch *BatchADV) controlErrors() []error {
+	var errs []error
+	advControl := batch.GetADVControl()
+	if err := advControl.Validate(); err != nil {
+		errs = append(errs, err)
+	}
+	batchNumber := batch.header.BatchNumber
+	if batch.header.ODFIIdentification != advControl.ODFIIdentification {
+		msg := fmt.Sprintf(msgBatchHeaderControlEquality, batch.header.ODFIIdentification, advControl.ODFIIdentification)
+		errs = append(errs, &BatchError{BatchNumber: batchNumber, FieldName: "ODFIIdentification", Msg: msg})
+	}
+	if batch.header.BatchNumber != advControl.BatchNumber {
+		msg := fmt.Sprintf(msgBatchHeaderControlEquality, batch.header.BatchNumber, advControl.BatchNumber)
+		errs = append(errs, &BatchError{BatchNumber: batchNumber, FieldName: "BatchNumber", Msg: msg})
+	}
+	if batch.EntryAddendaCount() != advControl.EntryAddendaCount {
+		msg := fmt.Sprintf(msgBatchCalculatedControlEquality, batch.EntryAddendaCount(), advControl.EntryAddendaCount)
+		errs = append(errs, &BatchError{BatchNumber: batchNumber, FieldName: "EntryAddendaCount", Msg: msg})
+	}
+	credit, debit := batch.calculateADVAmounts()
+	if debit != advControl.TotalDebitEntryDollarAmount {
+		msg := fmt.Sprintf(msgBatchCalculatedControlEquality, debit, advControl.TotalDebitEntryDollarAmount)
+		errs = append(errs, &BatchError{BatchNumber: batchNumber, FieldName: "TotalDebitEntryDollarAmount", Msg: msg})
+	}
+	if credit != advControl.TotalCreditEntryDollarAmount {
+		msg := fmt.Sprintf(msgBatchCalculatedControlEquality, credit, advControl.TotalCreditEntryDollarAmount)
+		errs = append(errs, &BatchError{BatchNumber: batchNumber, FieldName: "TotalCreditEntryDollarAmount", Msg: msg})
+	}
+	if hash := batch.calculateADVEntryHash(); hash != advControl.EntryHashField() {
+		msg := fmt.Sprintf(msgBatchCalculatedControlEquality, hash, advControl.EntryHashField())
+		errs = append(errs, &BatchError{BatchNumber: batchNumber, FieldName: "EntryHash", Msg: msg})
+	}
+	return errs
+}
+
+// Create builds the ADV batch control from the ADV entries and validates the batch.
+func (batch *BatchADV) Create() error {
+	if err := batch.header.Validate(); err != nil {
+		return err
+	}
+	if len(batch.advEntries) <= 0 {
+		return &BatchError{BatchNumber: batch.header.BatchNumber, FieldName: "entries", Msg: msgBatchEntries}
+	}
+	bc := NewADVBatchControl()
+	bc.ServiceClassCode = batch.header.ServiceClassCode
+	bc.ACHOperatorData = batch.advControl.ACHOperatorData
+	bc.ODFIIdentification = batch.header.ODFIIdentification
+	bc.BatchNumber = batch.header.BatchNumber
+	bc.EntryAddendaCount = batch.EntryAddendaCount()
+	bc.EntryHash = batch.parseNumField(batch.calculateADVEntryHash())
+	bc.TotalCreditEntryDollarAmount, bc.TotalDebitEntryDollarAmount = batch.calculateADVAmounts()
+	batch.SetADVControl(bc)
+
+	return batch.Validate()
+}
+
+// NetAmount returns the total credit amount minus the total debit amount of the ADV entries in the batch.
+func (batch *BatchADV) NetAmount() int {
+	credit, debit := batch.calculateADVAmounts()
+	return credit - debit
+}
+
+// EntryAddendaCount returns the number of ADV entry records in the batch. ADV entries do not have addenda.
+func (batch *BatchADV) EntryAddendaCount() int {
+	return len(batch.advEntries)
+}
+
+// isADV returns true if the batch header is for automated accounting advices, which have the
+// SEC code ADV or service class code 280.
+func (bh *BatchHeader) isADV() bool {
+	return bh.StandardEntryClassCode == "ADV" || bh.ServiceClassCode == 280
+}
+
+// fieldError converts a FieldError to a BatchError for a consistent api
+func (batch *BatchADV) fieldError(err error) error {
+	if e, ok := err.(*FieldError); ok {
+		return &BatchError{BatchNumber: batch.header.BatchNumber, FieldName: e.FieldName, Msg: e.Msg}
+	}
+	return &BatchError{BatchNumber: batch.header.BatchNumber, FieldName: "FieldError", Msg: err.Error()}
+}
+
+func (batch *BatchADV) calculateADVAmounts() (credit int, debit int) {
+	for _, entry := range batch.advEntries {
+		if entry.isCredit() {
+			credit = credit + entry.Amount
+		} else {
+			debit = debit + entry.Amount
+		}
+	}
+	return credit, debit
+}
+
+// calculateADVEntryHash hashes the 8-digit RDFI routing number of each ADV entry
+func (batch *BatchADV) calculateADVEntryHash() string {
+	hash := 0
+	for _, entry := range batch.advEntries {
+		hash = hash + entry.RDFIIdentification
+	}
+	return batch.numericField(hash, 10)
+}
@@ -0,0 +1,305 @@
+// Copyright 2017 The ACH Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package ach
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func mockBatchADVHeader() *BatchHeader {
+	bh := NewBatchHeader()
+	bh.ServiceClassCode = 280
+	bh.StandardEntryClassCode = "ADV"
+	bh.CompanyName = "ACH Operator"
+	bh.CompanyIdentification = "121042882"
+	bh.CompanyEntryDescription = "ADV FILE"
+	bh.ODFIIdentification = 12104288
+	return bh
+}
+
+func mockADVEntryDetail() *ADVEntryDetail {
+	entry := NewADVEntryDetail()
+	entry.TransactionCode = 81
+	entry.SetRDFI(231380104)
+	entry.DFIAccountNumber = "744-5678-99"
+	entry.Amount = 50000
+	entry.AdviceRoutingNumber = "121042882"
+	entry.FileIdentification = "11131"
+	entry.ACHOperatorData = ""
+	entry.IndividualName = "Name"
+	entry.DiscretionaryData = ""
+	entry.AddendaRecordIndicator = 0
+	entry.ACHOperatorRoutingNumber = "01100001"
+	entry.JulianDay = 50
+	entry.SequenceNumber = 1
+	return entry
+}
+
+func mockBatchADV() *BatchADV {
+	mockBatch := NewBatchADV()
+	mockBatch.SetHeader(mockBatchADVHeader())
+	mockBatch.AddADVEntry(mockADVEntryDetail())
+	debit := mockADVEntryDetail()
+	debit.TransactionCode = 82
+	debit.Amount = 20000
+	debit.SequenceNumber = 2
+	mockBatch.AddADVEntry(debit)
+	if err := mockBatch.Create(); err != nil {
+		panic(err)
+	}
+	return mockBatch
+}
+
+func TestADVEntryDetailParse(t *testing.T) {
+	entry := mockADVEntryDetail()
+	record := entry.String()
+	if len(record) != RecordLength {
+		t.Fatalf("record length %d", len(record))
+	}
+	parsed := NewADVEntryDetail()
+	parsed.Parse(record)
+	if err := parsed.Validate(); err != nil {
+		t.Fatalf("%T: %s", err, err)
+	}
+	if parsed.String() != record {
+		t.Errorf("expected %q got %q", record, parsed.String())
+	}
+	if parsed.AdviceRoutingNumber != "121042882" || parsed.JulianDay != 50 || parsed.Amount != 50000 {
+		t.Errorf("unexpected parsed entry %+v", parsed)
+	}
+}
+
+func TestADVEntryDetailTransactionCode(t *testing.T) {
+	entry := mockADVEntryDetail()
+	entry.TransactionCode = 22
+	if err := entry.Validate(); err != nil {
+		if e, ok := err.(*FieldError); ok {
+			if e.FieldName != "TransactionCode" {
+				t.Errorf("%T: %s", err, err)
+			}
+		}
+	} else {
+		t.Error("expected TransactionCode error")
+	}
+}
+
+func TestBatchADVCreate(t *testing.T) {
+	batch := mockBatchADV()
+	bc := batch.GetADVControl()
+	if bc.EntryAddendaCount != 2 {
+		t.Errorf("EntryAddendaCount %d", bc.EntryAddendaCount)
+	}
+	if bc.TotalCreditEntryDollarAmount != 50000 || bc.TotalDebitEntryDollarAmount != 20000 {
+		t.Errorf("credit %d debit %d", bc.TotalCreditEntryDollarAmount, bc.TotalDebitEntryDollarAmount)
+	}
+	if bc.EntryHash != 2*23138010 {
+		t.Errorf("EntryHash %d", bc.EntryHash)
+	}
+	if batch.GetControl().EntryHash != bc.EntryHash {
+		t.Errorf("BatchControl EntryHash %d", batch.GetControl().EntryHash)
+	}
+	if len(bc.String()) != RecordLength {
+		t.Errorf("record length %d", len(bc.String()))
+	}
+}
+
+func TestBatchADVServiceClassCode(t *testing.T) {
+	batch := mockBatchADV()
+	batch.GetHeader().ServiceClassCode = 220
+	if err := batch.Validate(); err != nil {
+		if e, ok := err.(*BatchError); ok {
+			if e.FieldName != "ServiceClassCode" {
+				t.Errorf("%T: %s", err, err)
+			}
+		} else {
+			t.Errorf("expected BatchError got: %v", err)
+		}
+	} else {
+		t.Error("expected ServiceClassCode error")
+	}
+}
+
+func TestBatchADVServiceClassCodeMixed(t *testing.T) {
+	batch := mockBatchADV()
+	batch.GetHeader().ServiceClassCode = 200
+	if err := batch.Create(); err != nil {
+		t.Fatalf("%T: %s", err, err)
+	}
+	if batch.GetADVControl().ServiceClassCode != 200 {
+		t.Errorf("ServiceClassCode got: %d", batch.GetADVControl().ServiceClassCode)
+	}
+}
+
+func TestBatchADVOffset(t *testing.T) {
+	batch := mockBatchADV()
+	if err := batch.WithOffset(&Offset{RoutingNumber: "231380104", AccountNumber: "123456789", Description: "OFFSET"}); err != nil {
+		if e, ok := err.(*BatchError); ok {
+			if e.FieldName != "StandardEntryClassCode" {
+				t.Errorf("%T: %s", err, err)
+			}
+		} else {
+			t.Errorf("expected BatchError got: %v", err)
+		}
+	} else {
+		t.Error("expected StandardEntryClassCode error")
+	}
+	if len(batch.GetEntries()) != 0 {
+		t.Errorf("offset entries were added: %d", len(batch.GetEntries()))
+	}
+	if err := batch.Validate(); err != nil {
+		t.Errorf("%T: %s", err, err)
+	}
+}
+
+func TestADVFileSplitByLineCount(t *testing.T) {
+	batch := mockBatchADV()
+	for i := 3; i <= 8; i++ {
+		entry := mockADVEntryDetail()
+		entry.SequenceNumber = i
+		batch.AddADVEntry(entry)
+	}
+	batch.GetADVControl().ACHOperatorData = "OPERATOR"
+	if err := batch.Create(); err != nil {
+		t.Fatalf("%T: %s", err, err)
+	}
+	file := NewFile().SetHeader(mockFileHeader())
+	file.AddBatch(batch)
+	if err := file.Create(); err != nil {
+		t.Fatalf("%T: %s", err, err)
+	}
+	if file.AddendaCount() != 0 {
+		t.Errorf("AddendaCount got: %d", file.AddendaCount())
+	}
+	files, err := file.SplitByLineCount(10)
+	if err != nil {
+		t.Fatalf("%T: %s", err, err)
+	}
+	if len(files) != 2 {
+		t.Fatalf("expected 2 files got: %d", len(files))
+	}
+	seq := 1
+	for i, f := range files {
+		split, ok := f.Batches[0].(*BatchADV)
+		if !ok {
+			t.Fatalf("file %d unexpected batch %T", i, f.Batches[0])
+		}
+		if split.GetADVControl().ACHOperatorData != "OPERATOR" {
+			t.Errorf("file %d ACHOperatorData got: %q", i, split.GetADVControl().ACHOperatorData)
+		}
+		for _, entry := range split.GetADVEntries() {
+			if entry.SequenceNumber != seq {
+				t.Errorf("file %d unexpected ADV entry %+v", i, entry)
+			}
+			seq++
+		}
+		if f.Control.EntryAddendaCount != len(split.GetADVEntries()) {
+			t.Errorf("file %d EntryAddendaCount got: %d", i, f.Control.EntryAddendaCount)
+		}
+	}
+	if _, err := file.SplitByLineCount(9); err == nil {
+		t.Error("expected error for an ADV entry that does not fit")
+	}
+}
+
+func TestBatchADVAmount(t *testing.T) {
+	batch := mockBatchADV()
+	batch.GetADVEntries()[0].Amount = 1
+	if err := batch.Validate(); err != nil {
+		if e, ok := err.(*BatchError); ok {
+			if e.FieldName != "TotalCreditEntryDollarAmount" {
+				t.Errorf("%T: %s", err, err)
+			}
+		} else {
+			t.Errorf("expected BatchError got: %v", err)
+		}
+	} else {
+		t.Error("expected TotalCreditEntryDollarAmount error")
+	}
+}
+
+func TestADVFileRoundTrip(t *testing.T) {
+	file := NewFile().SetHeader(mockFileHeader())
+	file.AddBatch(mockBatchADV())
+	file.AddBatch(mockBatchADV())
+	if err := file.Create(); err != nil {
+		t.Fatalf("%T: %s", err, err)
+	}
+	if !file.IsADV() {
+		t.Error("expected IsADV")
+	}
+
+	b := &bytes.Buffer{}
+	if err := NewWriter(b).WriteAll([]*File{file}); err != nil {
+		t.Fatalf("%T: %s", err, err)
+	}
+	lines := strings.Split(strings.TrimSuffix(b.String(), "\n"), "\n")
+	if control := lines[9]; control[:1] != "9" || control[31:51] != "00000000000000040000" {
+		t.Errorf("unexpected ADV File Control %q", control)
+	}
+
+	r := NewReader(strings.NewReader(b.String()))
+	read, err := r.Read()
+	if err != nil {
+		t.Fatalf("%T: %s", err, err)
+	}
+	if err := read.Validate(); err != nil {
+		t.Fatalf("%T: %s", err, err)
+	}
+	batch, ok := read.Batches[1].(*BatchADV)
+	if !ok {
+		t.Fatalf("expected *BatchADV got %T", read.Batches[1])
+	}
+	if len(batch.GetADVEntries()) != 2 || batch.GetADVControl().BatchNumber != 2 {
+		t.Errorf("unexpected batch %+v", batch.GetADVControl())
+	}
+
+	out := &bytes.Buffer{}
+	if err := NewWriter(out).WriteAll([]*File{&read}); err != nil {
+		t.Fatalf("%T: %s", err, err)
+	}
+	if out.String() != b.String() {
+		t.Errorf("expected\n%s\ngot\n%s", b.String(), out.String())
+	}
+}
+
+// TestADVReaderReset ensures a Reader does not parse the File Control of a file as ADV after
+// reading an ADV file
+func TestADVReaderReset(t *testing.T) {
+	b := &bytes.Buffer{}
+	if err := NewWriter(b).WriteAll([]*File{mockFilePPD()}); err != nil {
+		t.Fatalf("%T: %s", err, err)
+	}
+	r := NewReader(strings.NewReader(b.String()))
+	r.adv = true
+	file, err := r.Read()
+	if err != nil {
+		t.Fatalf("%T: %s", err, err)
+	}
+	if err := file.Validate(); err != nil {
+		t.Errorf("%T: %s", err, err)
+	}
+}
+
+func TestADVFileMixed(t *testing.T) {
+	file := NewFile().SetHeader(mockFileHeader())
+	file.AddBatch(mockBatchADV())
+	file.AddBatch(mockBatchPPD())
+	if err := file.Create(); err != nil {
+		t.Fatalf("%T: %s", err, err)
+	}
+	if err := file.Validate(); err != nil {
+		if e, ok := err.(*BatchError); ok {
+			if e.FieldName != "ServiceClassCode" {
+				t.Errorf("%T: %s", err, err)
+			}
+		} else {
+			t.Errorf("expected BatchError got: %v", err)
+		}
+	} else {
+		t.Error("expected ServiceClassCode error")
+	}
+}
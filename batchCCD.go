@@ -49,6 +49,10 @@ func (batch *BatchCCD) Validate() error {
 		return &BatchError{BatchNumber: batch.header.BatchNumber, FieldName: "StandardEntryClassCode", Msg: msg}
 	}
 
+	if err := batch.isEntrySEC(); err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -2,6 +2,7 @@ package ach
 
 import (
 	"testing"
+	"time"
 )
 
 func mockBatchCCDHeader() *BatchHeader {
@@ -12,6 +13,7 @@ func mockBatchCCDHeader() *BatchHeader {
 	bh.CompanyIdentification = "123456789"
 	bh.CompanyEntryDescription = "Vndr Pay"
 	bh.ODFIIdentification = 6200001
+	bh.EffectiveEntryDate = time.Now().AddDate(0, 0, 1)
 	return bh
 }
 
@@ -2,6 +2,7 @@ package ach
 
 import (
 	"fmt"
+	"strings"
 )
 
 type BatchCOR struct {
@@ -9,7 +10,7 @@ type BatchCOR struct {
 }
 
 var (
-	msgBatchWebPaymentType = "%v is not a valid payment type S (single entry) or R (recurring)"
+	msgBatchCORAddenda98 = "COR entries must have an Addenda98 record"
 )
 
 // NewBatchCOR returns a *BatchWEB
@@ -49,6 +50,15 @@ func (batch *BatchCOR) Validate() error {
 		msg := fmt.Sprintf(msgBatchSECType, batch.header.StandardEntryClassCode, "COR")
 		return &BatchError{BatchNumber: batch.header.BatchNumber, FieldName: "StandardEntryClassCode", Msg: msg}
 	}
+	for i, entry := range batch.entries {
+		if entry.Addenda98 == nil {
+			return batch.entryError(i, "Addenda98", msgBatchCORAddenda98)
+		}
+	}
+
+	if err := batch.isEntrySEC(); err != nil {
+		return err
+	}
 
 	return nil
 }
@@ -65,3 +75,52 @@ func (batch *BatchCOR) Create() error {
 	}
 	return nil
 }
+
+// BuildNOC returns a created COR batch with a Notification of Change for entry, an entry of the
+// batch with header bh. The NOC entry is sent by the RDFI of entry back to the ODFI of bh and
+// has an Addenda98 with changeCode and correctedData. correctedData must be laid out for
+// changeCode, for example the corrected routing number in the first 9 characters for C02. An
+// error is returned for change codes other than C01 through C13 or blank corrected data.
+func BuildNOC(bh *BatchHeader, entry *EntryDetail, changeCode string, correctedData string) (*BatchCOR, error) {
+	if !isChangeCode(changeCode) {
+		msg := fmt.Sprintf(msgAddenda98ChangeCodeNOC, changeCode)
+		return nil, &FieldError{FieldName: "ChangeCode", Value: changeCode, Msg: msg}
+	}
+	if strings.TrimSpace(correctedData) == "" {
+		msg := fmt.Sprintf(msgAddenda98CorrectedData, changeCode)
+		return nil, &FieldError{FieldName: "CorrectedData", Value: correctedData, Msg: msg}
+	}
+	if len(correctedData) > 29 {
+		msg := fmt.Sprintf(msgBatchFieldLength, correctedData, 29)
+		return nil, &FieldError{FieldName: "CorrectedData", Value: correctedData, Msg: msg}
+	}
+
+	header := *bh
+	header.StandardEntryClassCode = cor
+	header.ODFIIdentification = entry.RDFIIdentification
+	batch := NewBatchCOR()
+	batch.SetHeader(&header)
+
+	noc := NewEntryDetail()
+	// 21, 26, 31 and 36 are the notification of change codes for checking and savings
+	// credits and debits
+	noc.TransactionCode = entry.TransactionCode/5*5 + 1
+	noc.RDFIIdentification = bh.ODFIIdentification
+	noc.CheckDigit = noc.CalculateCheckDigit(noc.RDFIIdentificationField())
+	noc.DFIAccountNumber = entry.DFIAccountNumber
+	noc.IdentificationNumber = entry.IdentificationNumber
+	noc.IndividualName = entry.IndividualName
+	noc.AddendaRecordIndicator = 1
+	addenda98 := NewAddenda98()
+	addenda98.ChangeCode = changeCode
+	addenda98.OriginalTrace = entry.TraceNumber
+	addenda98.OriginalDFI = entry.RDFIIdentificationField()
+	addenda98.CorrectedData = correctedData
+	noc.Addenda98 = addenda98
+	batch.AddEntry(noc)
+
+	if err := batch.Create(); err != nil {
+		return nil, err
+	}
+	return batch, nil
+}
@@ -0,0 +1,124 @@
+// Copyright 2017 The ACH Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package ach
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func mockBatchCOR() *BatchCOR {
+	batch := mockBatchPPD()
+	cor, err := BuildNOC(batch.GetHeader(), batch.GetEntries()[0], "C01", "1918171614")
+	if err != nil {
+		panic(err)
+	}
+	return cor
+}
+
+func TestBuildNOC(t *testing.T) {
+	batch := mockBatchPPD()
+	entry := batch.GetEntries()[0]
+	cor := mockBatchCOR()
+	if cor.GetHeader().StandardEntryClassCode != "COR" {
+		t.Errorf("StandardEntryClassCode Expected COR got: %v", cor.GetHeader().StandardEntryClassCode)
+	}
+	if cor.GetHeader().ODFIIdentification != entry.RDFIIdentification {
+		t.Errorf("ODFIIdentification Expected %v got: %v", entry.RDFIIdentification, cor.GetHeader().ODFIIdentification)
+	}
+	noc := cor.GetEntries()[0]
+	if noc.TransactionCode != 21 || noc.Amount != 0 {
+		t.Errorf("NOC entry TransactionCode %v Amount %v", noc.TransactionCode, noc.Amount)
+	}
+	if noc.RDFIIdentificationField() != batch.GetHeader().ODFIIdentificationField() {
+		t.Errorf("RDFIIdentification Expected %v got: %v", batch.GetHeader().ODFIIdentificationField(), noc.RDFIIdentificationField())
+	}
+	a := noc.Addenda98
+	if a.ChangeCode != "C01" || a.OriginalTrace != entry.TraceNumber || a.OriginalDFI != entry.RDFIIdentificationField() {
+		t.Errorf("Addenda98 %+v", a)
+	}
+	if a.Trace != noc.TraceNumber {
+		t.Errorf("Addenda98 Trace Expected %v got: %v", noc.TraceNumber, a.Trace)
+	}
+	if cor.GetControl().EntryAddendaCount != 2 {
+		t.Errorf("EntryAddendaCount Expected 2 got: %v", cor.GetControl().EntryAddendaCount)
+	}
+	corrected, err := entry.ApplyNOC(a)
+	if err != nil {
+		t.Fatalf("%T: %s", err, err)
+	}
+	if corrected.DFIAccountNumber != "1918171614" {
+		t.Errorf("DFIAccountNumber Expected 1918171614 got: %v", corrected.DFIAccountNumber)
+	}
+}
+
+func TestBuildNOCChangeCode(t *testing.T) {
+	batch := mockBatchPPD()
+	for _, code := range []string{"C00", "C14", "R01", ""} {
+		_, err := BuildNOC(batch.GetHeader(), batch.GetEntries()[0], code, "1918171614")
+		if e, ok := err.(*FieldError); ok {
+			if e.FieldName != "ChangeCode" {
+				t.Errorf("%T: %s", err, err)
+			}
+		} else {
+			t.Errorf("%q expected FieldError got: %v", code, err)
+		}
+	}
+	for _, data := range []string{" ", strings.Repeat("1", 30)} {
+		_, err := BuildNOC(batch.GetHeader(), batch.GetEntries()[0], "C01", data)
+		if e, ok := err.(*FieldError); ok {
+			if e.FieldName != "CorrectedData" {
+				t.Errorf("%T: %s", err, err)
+			}
+		} else {
+			t.Errorf("%q expected FieldError got: %v", data, err)
+		}
+	}
+}
+
+// COR entries must have an Addenda98
+func TestBatchCORAddenda98(t *testing.T) {
+	cor := mockBatchCOR()
+	cor.GetEntries()[0].Addenda98 = nil
+	err := cor.Create()
+	if e, ok := err.(*BatchError); ok {
+		if e.FieldName != "Addenda98" {
+			t.Errorf("%T: %s", err, err)
+		}
+	} else {
+		t.Errorf("expected BatchError got: %v", err)
+	}
+}
+
+func TestBatchCORReadWrite(t *testing.T) {
+	file := NewFile().SetHeader(mockFileHeader())
+	file.AddBatch(mockBatchCOR())
+	if err := file.Create(); err != nil {
+		t.Fatalf("%T: %s", err, err)
+	}
+	b := &bytes.Buffer{}
+	if err := NewWriter(b).WriteAll([]*File{file}); err != nil {
+		t.Fatalf("%T: %s", err, err)
+	}
+	read, err := NewReader(strings.NewReader(b.String())).Read()
+	if err != nil {
+		t.Fatalf("%T: %s", err, err)
+	}
+	a := read.Batches[0].GetEntries()[0].Addenda98
+	if a == nil {
+		t.Fatal("expected Addenda98 to be read")
+	}
+	if a.String() != file.Batches[0].GetEntries()[0].Addenda98.String() {
+		t.Errorf("Addenda98 got:\n%s\nwant:\n%s", a, file.Batches[0].GetEntries()[0].Addenda98)
+	}
+	out := &bytes.Buffer{}
+	if err := NewWriter(out).WriteAll([]*File{&read}); err != nil {
+		t.Fatalf("%T: %s", err, err)
+	}
+	if out.String() != b.String() {
+		t.Errorf("COR file does not round trip\ngot:\n%s\nwant:\n%s", out, b)
+	}
+}
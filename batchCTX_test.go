@@ -0,0 +1,89 @@
+// Copyright 2017 The ACH Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package ach
+
+import (
+	"testing"
+	"time"
+)
+
+func mockBatchCTXHeader() *BatchHeader {
+	bh := NewBatchHeader()
+	bh.ServiceClassCode = 220
+	bh.StandardEntryClassCode = "CTX"
+	bh.CompanyName = "Your Company, inc"
+	bh.CompanyIdentification = "123456789"
+	bh.CompanyEntryDescription = "Vndr Pay"
+	bh.ODFIIdentification = 6200001
+	bh.EffectiveEntryDate = time.Now().AddDate(0, 0, 1)
+	return bh
+}
+
+func mockCTXEntryDetail() *EntryDetail {
+	entry := NewEntryDetail()
+	entry.TransactionCode = 22
+	entry.SetRDFI(9101298)
+	entry.DFIAccountNumber = "744-5678-99"
+	entry.Amount = 5000000
+	entry.IdentificationNumber = "location #23"
+	entry.SetCATXReceivingCompany("Best Co. #23")
+	entry.TraceNumber = 123456789
+	return entry
+}
+
+func mockBatchCTX() *BatchCTX {
+	mockBatch := NewBatchCTX()
+	mockBatch.SetHeader(mockBatchCTXHeader())
+	mockBatch.AddEntry(mockCTXEntryDetail())
+	mockBatch.GetEntries()[0].AddAddenda(mockAddenda())
+	mockBatch.GetEntries()[0].AddAddenda(mockAddenda())
+	if err := mockBatch.Create(); err != nil {
+		panic(err)
+	}
+	return mockBatch
+}
+
+// Create sets the number of addenda records on each entry
+func TestBatchCTXCreate(t *testing.T) {
+	mockBatch := mockBatchCTX()
+	entry := mockBatch.GetEntries()[0]
+	if entry.CATXAddendaRecordsField() != "0002" {
+		t.Errorf("CATXAddendaRecords Expected '0002' got: %v", entry.CATXAddendaRecordsField())
+	}
+	if entry.CATXReceivingCompanyField() != "Best Co. #23    " {
+		t.Errorf("CATXReceivingCompany Expected 'Best Co. #23    ' got: %v", entry.CATXReceivingCompanyField())
+	}
+}
+
+// The number of addenda records in the entry must match the addendum
+func TestBatchCTXAddendaRecords(t *testing.T) {
+	mockBatch := mockBatchCTX()
+	mockBatch.GetEntries()[0].SetCATXAddendaRecords(5)
+	if err := mockBatch.Validate(); err != nil {
+		if e, ok := err.(*BatchError); ok {
+			if e.FieldName != "CATXAddendaRecords" {
+				t.Errorf("%T: %s", err, err)
+			}
+		} else {
+			t.Errorf("%T: %s", err, err)
+		}
+	} else {
+		t.Error("expected error for CATXAddendaRecords mismatch")
+	}
+}
+
+func TestBatchCTXSEC(t *testing.T) {
+	mockBatch := mockBatchCTX()
+	mockBatch.GetHeader().StandardEntryClassCode = "RCK"
+	if err := mockBatch.Validate(); err != nil {
+		if e, ok := err.(*BatchError); ok {
+			if e.FieldName != "StandardEntryClassCode" {
+				t.Errorf("%T: %s", err, err)
+			}
+		} else {
+			t.Errorf("%T: %s", err, err)
+		}
+	}
+}
@@ -69,7 +69,9 @@ type BatchControl struct {
 }
 
 // Parse takes the input record string and parses the EntryDetail values
+// A record shorter than RecordLength is padded with spaces.
 func (bc *BatchControl) Parse(record string) {
+	record = bc.padRecord(record)
 	// 1-1 Always "8"
 	bc.recordType = "8"
 	// 2-4 This is the same as the "Service code" field in previous Batch Header Record
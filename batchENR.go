@@ -0,0 +1,179 @@
+// Copyright 2017 The ACH Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package ach
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// BatchENR creates a batch file that handles SEC payment type ENR.
+// Automated Enrollment Entry. A non-monetary entry sent by a Federal Government agency to enroll
+// a receiver's account for benefit payments. The enrollment of each entry is carried in the
+// PaymentRelatedInformation of its Addenda05 records.
+type BatchENR struct {
+	batch
+}
+
+var (
+	msgBatchENRAddenda       = "ENR entries must have an addenda record"
+	msgENRPaymentInformation = "must have %d fields separated by * and ending with \\ and found %d"
+)
+
+// NewBatchENR returns a *BatchENR
+func NewBatchENR(params ...BatchParam) *BatchENR {
+	batch := new(BatchENR)
+	batch.SetControl(NewBatchControl())
+
+	if len(params) > 0 {
+		bh := NewBatchHeader(params[0])
+		bh.StandardEntryClassCode = enr
+		batch.SetHeader(bh)
+		return batch
+	}
+	bh := NewBatchHeader()
+	bh.StandardEntryClassCode = enr
+	batch.SetHeader(bh)
+	return batch
+}
+
+// Validate ensures the batch meets NACHA rules specific to this batch type.
+func (batch *BatchENR) Validate() error {
+	// basic verification of the batch before we validate specific rules.
+	if err := batch.verify(); err != nil {
+		return err
+	}
+	// Add configuration based validation for this type.
+	// ENR can have up to 9,999 addenda per entry record
+	if err := batch.isAddendaCount(9999); err != nil {
+		return err
+	}
+	if err := batch.isTypeCode("05"); err != nil {
+		return err
+	}
+
+	// Add type specific validation.
+	if batch.header.StandardEntryClassCode != enr {
+		msg := fmt.Sprintf(msgBatchSECType, batch.header.StandardEntryClassCode, enr)
+		return &BatchError{BatchNumber: batch.header.BatchNumber, FieldName: "StandardEntryClassCode", Msg: msg}
+	}
+
+	if err := batch.isEntrySEC(); err != nil {
+		return err
+	}
+
+	for i, entry := range batch.entries {
+		if len(entry.Addendum) == 0 {
+			return batch.entryError(i, "Addendum", msgBatchENRAddenda)
+		}
+		for _, addenda := range entry.Addendum {
+			if _, err := addenda.ENRPaymentInformation(); err != nil {
+				if e, ok := err.(*FieldError); ok {
+					return batch.entryError(i, e.FieldName, e.Msg)
+				}
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// Create builds the batch sequence numbers and batch control. Additional creation
+func (batch *BatchENR) Create() error {
+	// generates sequence numbers and batch control
+	if err := batch.build(); err != nil {
+		return err
+	}
+
+	if err := batch.Validate(); err != nil {
+		return err
+	}
+	return nil
+}
+
+// ENRPaymentInformation is the enrollment carried in the PaymentRelatedInformation of an ENR
+// Addenda05 record. The fields are separated by "*" and the last field ends with "\".
+type ENRPaymentInformation struct {
+	// TransactionCode of the account being enrolled. 22 checking or 32 savings
+	TransactionCode int
+	// RDFIIdentification is the first 8 digits of the routing number of the enrolled account
+	RDFIIdentification string
+	// CheckDigit is the last digit of the routing number of the enrolled account
+	CheckDigit int
+	// DFIAccountNumber is the enrolled account number
+	DFIAccountNumber string
+	// IndividualIdentification is the social security number of the individual or the
+	// taxpayer identification number of the company being enrolled
+	IndividualIdentification string
+	// IndividualSurname is the surname of the individual or the company name
+	IndividualSurname string
+	// IndividualFirstName is the first name of the individual or the continued company name
+	IndividualFirstName string
+	// RepresentativePayeeIndicator is the representative payee indicator of an individual
+	// or the enrollee classification code of a company
+	RepresentativePayeeIndicator string
+
+	// validator is composed for data validation
+	validator
+}
+
+// enrPaymentInformationFields is the number of fields in an ENRPaymentInformation
+const enrPaymentInformationFields = 8
+
+// ENRPaymentInformation parses the PaymentRelatedInformation of an ENR addenda. A FieldError is
+// returned if it is not in the ENR enrollment format.
+func (addenda *Addenda) ENRPaymentInformation() (*ENRPaymentInformation, error) {
+	info := addenda.PaymentRelatedInformation
+	fields := strings.Split(strings.TrimSuffix(info, `\`), "*")
+	if !strings.HasSuffix(info, `\`) || len(fields) != enrPaymentInformationFields {
+		msg := fmt.Sprintf(msgENRPaymentInformation, enrPaymentInformationFields, len(fields))
+		return nil, &FieldError{FieldName: "PaymentRelatedInformation", Value: info, Msg: msg}
+	}
+	enrollment := &ENRPaymentInformation{
+		RDFIIdentification:           fields[1],
+		DFIAccountNumber:             fields[3],
+		IndividualIdentification:     fields[4],
+		IndividualSurname:            fields[5],
+		IndividualFirstName:          fields[6],
+		RepresentativePayeeIndicator: fields[7],
+	}
+	var err error
+	if enrollment.TransactionCode, err = strconv.Atoi(fields[0]); err != nil {
+		return nil, &FieldError{FieldName: "TransactionCode", Value: fields[0], Msg: msgNumeric}
+	}
+	if enrollment.CheckDigit, err = strconv.Atoi(fields[2]); err != nil || len(fields[2]) != 1 {
+		return nil, &FieldError{FieldName: "CheckDigit", Value: fields[2], Msg: msgNumeric}
+	}
+	if err := enrollment.Validate(); err != nil {
+		return nil, err
+	}
+	return enrollment, nil
+}
+
+// Validate performs NACHA format rule checks on the enrollment and returns an error if not Validated
+func (enrollment *ENRPaymentInformation) Validate() error {
+	if err := enrollment.isTransactionCode(enrollment.TransactionCode); err != nil {
+		return &FieldError{FieldName: "TransactionCode", Value: strconv.Itoa(enrollment.TransactionCode), Msg: err.Error()}
+	}
+	if len(enrollment.RDFIIdentification) != 8 {
+		msg := fmt.Sprintf(msgValidFieldLength, 8)
+		return &FieldError{FieldName: "RDFIIdentification", Value: enrollment.RDFIIdentification, Msg: msg}
+	}
+	if err := enrollment.isNumeric(enrollment.RDFIIdentification); err != nil {
+		return &FieldError{FieldName: "RDFIIdentification", Value: enrollment.RDFIIdentification, Msg: err.Error()}
+	}
+	if calculated := enrollment.CalculateCheckDigit(enrollment.RDFIIdentification); calculated != enrollment.CheckDigit {
+		msg := fmt.Sprintf(msgValidCheckDigit, calculated)
+		return &FieldError{FieldName: "CheckDigit", Value: strconv.Itoa(enrollment.CheckDigit), Msg: msg}
+	}
+	if enrollment.DFIAccountNumber == "" {
+		return &FieldError{FieldName: "DFIAccountNumber", Value: enrollment.DFIAccountNumber, Msg: msgFieldInclusion}
+	}
+	if enrollment.IndividualIdentification == "" {
+		return &FieldError{FieldName: "IndividualIdentification", Value: enrollment.IndividualIdentification, Msg: msgFieldInclusion}
+	}
+	return nil
+}
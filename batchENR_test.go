@@ -0,0 +1,181 @@
+// Copyright 2017 The ACH Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package ach
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+func mockBatchENRHeader() *BatchHeader {
+	bh := NewBatchHeader()
+	bh.ServiceClassCode = 220
+	bh.StandardEntryClassCode = "ENR"
+	bh.CompanyName = "DEPT OF TREASURY"
+	bh.CompanyIdentification = "2234567890"
+	bh.CompanyEntryDescription = "AUTOENROLL"
+	bh.ODFIIdentification = 6200001
+	bh.EffectiveEntryDate = time.Now().AddDate(0, 0, 1)
+	return bh
+}
+
+func mockENREntryDetail() *EntryDetail {
+	entry := NewEntryDetail()
+	entry.TransactionCode = 22
+	entry.SetRDFI(9101298)
+	entry.DFIAccountNumber = "123456789"
+	entry.Amount = 0
+	entry.IdentificationNumber = "ABC##jvkdjfuiwn"
+	entry.IndividualName = "Bank of America N.A."
+	entry.TraceNumber = 123456789
+	return entry
+}
+
+func mockENRAddenda() Addenda {
+	addenda := mockAddenda()
+	addenda.PaymentRelatedInformation = `22*09101298*4*123987654321*777777777*DOE*JOHN*0\`
+	return addenda
+}
+
+func mockBatchENR() *BatchENR {
+	mockBatch := NewBatchENR()
+	mockBatch.SetHeader(mockBatchENRHeader())
+	mockBatch.AddEntry(mockENREntryDetail())
+	mockBatch.GetEntries()[0].AddAddenda(mockENRAddenda())
+	if err := mockBatch.Create(); err != nil {
+		panic(err)
+	}
+	return mockBatch
+}
+
+func TestBatchENRCreate(t *testing.T) {
+	mockBatch := mockBatchENR()
+	if err := mockBatch.Validate(); err != nil {
+		t.Errorf("%T: %s", err, err)
+	}
+}
+
+func TestENRPaymentInformation(t *testing.T) {
+	addenda := mockENRAddenda()
+	enrollment, err := addenda.ENRPaymentInformation()
+	if err != nil {
+		t.Fatalf("%T: %s", err, err)
+	}
+	if enrollment.TransactionCode != 22 || enrollment.RDFIIdentification != "09101298" || enrollment.CheckDigit != 4 {
+		t.Errorf("unexpected routing %+v", enrollment)
+	}
+	if enrollment.DFIAccountNumber != "123987654321" || enrollment.IndividualIdentification != "777777777" {
+		t.Errorf("unexpected account %+v", enrollment)
+	}
+	if enrollment.IndividualSurname != "DOE" || enrollment.IndividualFirstName != "JOHN" || enrollment.RepresentativePayeeIndicator != "0" {
+		t.Errorf("unexpected name %+v", enrollment)
+	}
+}
+
+func TestENRPaymentInformationFormat(t *testing.T) {
+	tests := map[string]string{
+		`22*09101298*4*123987654321*777777777*DOE*JOHN*0`:  "PaymentRelatedInformation",
+		`22*09101298*4*123987654321*777777777*DOE*JOHN\`:   "PaymentRelatedInformation",
+		`99*09101298*4*123987654321*777777777*DOE*JOHN*0\`: "TransactionCode",
+		`22*0910129*4*123987654321*777777777*DOE*JOHN*0\`:  "RDFIIdentification",
+		`22*09101298*5*123987654321*777777777*DOE*JOHN*0\`: "CheckDigit",
+		`22*09101298*4**777777777*DOE*JOHN*0\`:             "DFIAccountNumber",
+	}
+	for info, fieldName := range tests {
+		addenda := mockENRAddenda()
+		addenda.PaymentRelatedInformation = info
+		_, err := addenda.ENRPaymentInformation()
+		if e, ok := err.(*FieldError); ok {
+			if e.FieldName != fieldName {
+				t.Errorf("%s: %T: %s", info, err, err)
+			}
+			if e.FieldName == "RDFIIdentification" && e.Msg != fmt.Sprintf(msgValidFieldLength, 8) {
+				t.Errorf("%s: %T: %s", info, err, err)
+			}
+		} else {
+			t.Errorf("%s: expected FieldError got: %v", info, err)
+		}
+	}
+}
+
+func TestBatchENRAmount(t *testing.T) {
+	mockBatch := mockBatchENR()
+	mockBatch.GetEntries()[0].Amount = 100
+	if err := mockBatch.Create(); err != nil {
+		if e, ok := err.(*BatchError); ok {
+			if e.FieldName != "Amount" {
+				t.Errorf("%T: %s", err, err)
+			}
+		} else {
+			t.Errorf("expected BatchError got: %v", err)
+		}
+	} else {
+		t.Error("expected Amount error")
+	}
+}
+
+func TestBatchENRAddenda(t *testing.T) {
+	mockBatch := NewBatchENR()
+	mockBatch.SetHeader(mockBatchENRHeader())
+	mockBatch.AddEntry(mockENREntryDetail())
+	if err := mockBatch.Create(); err != nil {
+		if e, ok := err.(*BatchError); ok {
+			if e.FieldName != "Addendum" {
+				t.Errorf("%T: %s", err, err)
+			}
+		} else {
+			t.Errorf("expected BatchError got: %v", err)
+		}
+	} else {
+		t.Error("expected Addendum error")
+	}
+
+	mockBatch.GetEntries()[0].AddAddenda(mockAddenda())
+	if err := mockBatch.Create(); err != nil {
+		if e, ok := err.(*BatchError); ok {
+			if e.FieldName != "PaymentRelatedInformation" {
+				t.Errorf("%T: %s", err, err)
+			}
+		} else {
+			t.Errorf("expected BatchError got: %v", err)
+		}
+	} else {
+		t.Error("expected PaymentRelatedInformation error")
+	}
+}
+
+func TestBatchENRRoundTrip(t *testing.T) {
+	file := NewFile().SetHeader(mockFileHeader())
+	file.AddBatch(mockBatchENR())
+	if err := file.Create(); err != nil {
+		t.Fatalf("%T: %s", err, err)
+	}
+	b := &bytes.Buffer{}
+	if err := NewWriter(b).WriteAll([]*File{file}); err != nil {
+		t.Fatalf("%T: %s", err, err)
+	}
+	r := NewReader(strings.NewReader(b.String()))
+	read, err := r.Read()
+	if err != nil {
+		t.Fatalf("%T: %s", err, err)
+	}
+	if _, ok := read.Batches[0].(*BatchENR); !ok {
+		t.Fatalf("expected *BatchENR got %T", read.Batches[0])
+	}
+	addenda := read.Batches[0].GetEntries()[0].Addendum[0]
+	if addenda.PaymentRelatedInformation != mockENRAddenda().PaymentRelatedInformation {
+		t.Errorf("PaymentRelatedInformation got %q", addenda.PaymentRelatedInformation)
+	}
+	out := &bytes.Buffer{}
+	if err := NewWriter(out).WriteAll([]*File{&read}); err != nil {
+		t.Fatalf("%T: %s", err, err)
+	}
+	if out.String() != b.String() {
+		t.Errorf("expected\n%s\ngot\n%s", b.String(), out.String())
+	}
+}
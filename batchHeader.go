@@ -11,7 +11,13 @@ import (
 	"time"
 )
 
-// msgServiceClass
+// Errors specific to a Batch Header Record
+var (
+	msgCompanyIDLength = "is not length %d for SEC %v"
+	msgCompanyIDICD    = "does not begin with an Identification Code Designator 1, 3 or 9 for SEC %v"
+	msgCompanyIDNumber = "does not have a 9 digit identification number following the designator for SEC %v"
+	msgCompanyNameLength = "is %d characters and would be truncated to the %d character field"
+)
 
 // BatchHeader identifies the originating entity and the type of transactions
 // contained in the batch (i.e., the standard entry class, PPD for consumer, CCD
@@ -69,7 +75,8 @@ type BatchHeader struct {
 	// entries in this field are "011392,", "01 92," "JAN 13," "JAN 92," etc.
 	CompanyDescriptiveDate string
 
-	// EffectiveEntryDate the date on which the entries are to settle
+	// EffectiveEntryDate the date on which the entries are to settle. The date
+	// applies to every entry in the batch as Entry Detail Records have no date of their own.
 	EffectiveEntryDate time.Time
 
 	// SettlementDate Leave blank, this field is inserted by the ACH operator
@@ -87,6 +94,8 @@ type BatchHeader struct {
 	// the ascending sequence number should be assigned by batch and not by
 	// record.
 	BatchNumber int
+	// Raw is the record as it was read when the Reader has the PreserveRawRecords option
+	Raw *RawRecord
 
 	// validator is composed for data validation
 	validator
@@ -117,7 +126,9 @@ func NewBatchHeader(params ...BatchParam) *BatchHeader {
 }
 
 // Parse takes the input record string and parses the BatchHeader values
+// A record shorter than RecordLength is padded with spaces.
 func (bh *BatchHeader) Parse(record string) {
+	record = bh.padRecord(record)
 	// 1-1 Always "5"
 	bh.recordType = "5"
 	// 2-4 If the entries are credits, always "220". If the entries are debits, always "225"
@@ -141,9 +152,10 @@ func (bh *BatchHeader) Parse(record string) {
 	bh.CompanyDescriptiveDate = strings.TrimSpace(record[63:69])
 	// 70-75 Date transactions are to be posted to the receivers’ account.
 	// You almost always want the transaction to post as soon as possible, so put tomorrow's date in YYMMDD format
+	// Blank for return and notification of change batches
 	bh.EffectiveEntryDate = bh.parseSimpleDate(record[69:75])
-	// 76-79 Always blank (just fill with spaces)
-	bh.settlementDate = "   "
+	// 76-78 The julian settlement date inserted by the ACH operator. Blank in files sent to the operator
+	bh.settlementDate = record[75:78]
 	// 79-79 Always 1
 	bh.OriginatorStatusCode = bh.parseNumField(record[78:79])
 	// 80-87 Your ODFI's routing number without the last digit. The last digit is simply a
@@ -208,6 +220,49 @@ func (bh *BatchHeader) Validate() error {
 	return nil
 }
 
+// ValidateCompanyIdentification checks CompanyIdentification against the format required
+// by the batch SEC code. The returned FieldError message describes the rule that failed.
+//
+// IAT uses the Originator Identification which is a free form alphanumeric value of up to
+// 10 characters. All other SEC codes use an ANSI Identification Code Designator (ICD)
+// followed by a 9 digit number:
+//
+// IRS Employer Identification Number (EIN) "1"
+// Data Universal Numbering Systems (DUNS) "3"
+// User Assigned Number "9"
+func (bh *BatchHeader) ValidateCompanyIdentification() error {
+	id := bh.CompanyIdentification
+	if id == "" {
+		return &FieldError{FieldName: "CompanyIdentification", Value: id, Msg: msgFieldInclusion}
+	}
+	switch bh.StandardEntryClassCode {
+	case "IAT":
+		if len(id) > 10 {
+			msg := fmt.Sprintf(msgCompanyIDLength, 10, bh.StandardEntryClassCode)
+			return &FieldError{FieldName: "CompanyIdentification", Value: id, Msg: msg}
+		}
+		if err := bh.isAlphanumeric(id); err != nil {
+			return &FieldError{FieldName: "CompanyIdentification", Value: id, Msg: err.Error()}
+		}
+	default:
+		if len(id) != 10 {
+			msg := fmt.Sprintf(msgCompanyIDLength, 10, bh.StandardEntryClassCode)
+			return &FieldError{FieldName: "CompanyIdentification", Value: id, Msg: msg}
+		}
+		switch id[:1] {
+		case "1", "3", "9":
+		default:
+			msg := fmt.Sprintf(msgCompanyIDICD, bh.StandardEntryClassCode)
+			return &FieldError{FieldName: "CompanyIdentification", Value: id, Msg: msg}
+		}
+		if err := bh.isNumeric(id[1:]); err != nil {
+			msg := fmt.Sprintf(msgCompanyIDNumber, bh.StandardEntryClassCode)
+			return &FieldError{FieldName: "CompanyIdentification", Value: id, Msg: msg}
+		}
+	}
+	return nil
+}
+
 // fieldInclusion validate mandatory fields are not default values. If fields are
 // invalid the ACH transfer will be returned.
 func (bh *BatchHeader) fieldInclusion() error {
@@ -217,7 +272,7 @@ func (bh *BatchHeader) fieldInclusion() error {
 	if bh.ServiceClassCode == 0 {
 		return &FieldError{FieldName: "ServiceClassCode", Value: strconv.Itoa(bh.ServiceClassCode), Msg: msgFieldInclusion}
 	}
-	if bh.CompanyName == "" {
+	if strings.TrimSpace(bh.CompanyName) == "" {
 		return &FieldError{FieldName: "CompanyName", Value: bh.CompanyName, Msg: msgFieldInclusion}
 	}
 	if bh.CompanyIdentification == "" {
@@ -239,6 +294,16 @@ func (bh *BatchHeader) fieldInclusion() error {
 	return nil
 }
 
+// isCompanyNameLength checks that CompanyName fits the 16 character field. CompanyNameField
+// silently truncates a longer name.
+func (bh *BatchHeader) isCompanyNameLength() error {
+	if len(bh.CompanyName) > 16 {
+		msg := fmt.Sprintf(msgCompanyNameLength, len(bh.CompanyName), 16)
+		return &FieldError{FieldName: "CompanyName", Value: bh.CompanyName, Msg: msg}
+	}
+	return nil
+}
+
 // CompanyNameField get the CompanyName left padded
 func (bh *BatchHeader) CompanyNameField() string {
 	return bh.alphaField(bh.CompanyName, 16)
@@ -264,8 +329,12 @@ func (bh *BatchHeader) CompanyDescriptiveDateField() string {
 	return bh.alphaField(bh.CompanyDescriptiveDate, 6)
 }
 
-// EffectiveEntryDateField get the EffectiveEntryDate in YYMMDD format
+// EffectiveEntryDateField get the EffectiveEntryDate in YYMMDD format. The field is blank
+// when EffectiveEntryDate is not set, as it is for return and notification of change batches.
 func (bh *BatchHeader) EffectiveEntryDateField() string {
+	if bh.EffectiveEntryDate.IsZero() {
+		return bh.alphaField("", 6)
+	}
 	return bh.formatSimpleDate(bh.EffectiveEntryDate)
 }
 
@@ -282,3 +351,14 @@ func (bh *BatchHeader) BatchNumberField() string {
 func (bh *BatchHeader) settlementDateField() string {
 	return bh.alphaField(bh.settlementDate, 3)
 }
+
+// SettlementDate returns the 3 digit julian day the ACH operator settles the batch or "" when
+// it is blank.
+func (bh *BatchHeader) SettlementDate() string {
+	return strings.TrimSpace(bh.settlementDate)
+}
+
+// SetSettlementDate sets the 3 digit julian settlement date. It is usually inserted by the ACH operator.
+func (bh *BatchHeader) SetSettlementDate(s string) {
+	bh.settlementDate = s
+}
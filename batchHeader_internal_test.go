@@ -5,8 +5,10 @@
 package ach
 
 import (
+	"fmt"
 	"strings"
 	"testing"
+	"time"
 )
 
 func mockBatchHeader() *BatchHeader {
@@ -17,6 +19,7 @@ func mockBatchHeader() *BatchHeader {
 	bh.CompanyIdentification = "123456789"
 	bh.CompanyEntryDescription = "PAYROLL"
 	bh.ODFIIdentification = 6200001
+	bh.EffectiveEntryDate = time.Now().AddDate(0, 0, 1)
 	return bh
 }
 
@@ -247,6 +250,22 @@ func TestBHFieldInclusionCompanyName(t *testing.T) {
 	}
 }
 
+func TestBHFieldInclusionCompanyNameBlank(t *testing.T) {
+	bh := mockBatchHeader()
+	bh.CompanyName = "                "
+	if err := bh.Validate(); err != nil {
+		if e, ok := err.(*FieldError); ok {
+			if e.FieldName != "CompanyName" {
+				t.Errorf("%T: %s", err, err)
+			}
+		} else {
+			t.Errorf("%T: %s", err, err)
+		}
+	} else {
+		t.Error("expected error for a blank CompanyName")
+	}
+}
+
 func TestBHFieldInclusionCompanyIdentification(t *testing.T) {
 	bh := mockBatchHeader()
 	bh.CompanyIdentification = ""
@@ -294,3 +313,41 @@ func TestBHFieldInclusionOriginatorStatusCode(t *testing.T) {
 		}
 	}
 }
+
+// TestBHCompanyIdentificationSEC ensure CompanyIdentification is validated per SEC code
+func TestBHCompanyIdentificationSEC(t *testing.T) {
+	bh := mockBatchHeader()
+	bh.CompanyIdentification = "1234567890"
+	if err := bh.ValidateCompanyIdentification(); err != nil {
+		t.Errorf("%T: %s", err, err)
+	}
+
+	tests := []struct {
+		sec string
+		id  string
+		msg string
+	}{
+		{"PPD", "123456789", fmt.Sprintf(msgCompanyIDLength, 10, "PPD")},
+		{"CCD", "5234567890", fmt.Sprintf(msgCompanyIDICD, "CCD")},
+		{"PPD", "12345A7890", fmt.Sprintf(msgCompanyIDNumber, "PPD")},
+		{"IAT", "ORIGINATOR ID", fmt.Sprintf(msgCompanyIDLength, 10, "IAT")},
+	}
+	for _, test := range tests {
+		bh.StandardEntryClassCode = test.sec
+		bh.CompanyIdentification = test.id
+		err := bh.ValidateCompanyIdentification()
+		if e, ok := err.(*FieldError); ok {
+			if e.FieldName != "CompanyIdentification" || e.Msg != test.msg {
+				t.Errorf("%T: %s", err, err)
+			}
+		} else {
+			t.Errorf("%s %s expected FieldError got: %v", test.sec, test.id, err)
+		}
+	}
+
+	bh.StandardEntryClassCode = "IAT"
+	bh.CompanyIdentification = "ORIG ID 42"
+	if err := bh.ValidateCompanyIdentification(); err != nil {
+		t.Errorf("%T: %s", err, err)
+	}
+}
@@ -0,0 +1,78 @@
+// Copyright 2017 The ACH Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package ach
+
+import (
+	"fmt"
+)
+
+// BatchPOP creates a batch file that handles SEC payment type POP.
+// Point-of-Purchase. A check presented in person at the point of purchase is converted to a
+// single debit entry. The check serial number and the city and state of the terminal where
+// the check was converted are carried in the IdentificationNumber field of the entry.
+type BatchPOP struct {
+	batch
+}
+
+// NewBatchPOP returns a *BatchPOP
+func NewBatchPOP(params ...BatchParam) *BatchPOP {
+	batch := new(BatchPOP)
+	batch.SetControl(NewBatchControl())
+
+	if len(params) > 0 {
+		bh := NewBatchHeader(params[0])
+		bh.StandardEntryClassCode = pop
+		batch.SetHeader(bh)
+		return batch
+	}
+	bh := NewBatchHeader()
+	bh.StandardEntryClassCode = pop
+	batch.SetHeader(bh)
+	return batch
+}
+
+// Validate ensures the batch meets NACHA rules specific to this batch type.
+func (batch *BatchPOP) Validate() error {
+	// basic verification of the batch before we validate specific rules.
+	if err := batch.verify(); err != nil {
+		return err
+	}
+	// Add configuration based validation for this type.
+	// POP entries have no addenda. Only a returned entry has a return addenda.
+	for i, entry := range batch.entries {
+		if len(entry.Addendum) > 0 {
+			msg := fmt.Sprintf(msgBatchAddendaCount, len(entry.Addendum), 0, pop)
+			return batch.entryError(i, "AddendaCount", msg)
+		}
+	}
+	if err := batch.isAddendaCount(1); err != nil {
+		return err
+	}
+
+	// Add type specific validation.
+	if batch.header.StandardEntryClassCode != pop {
+		msg := fmt.Sprintf(msgBatchSECType, batch.header.StandardEntryClassCode, pop)
+		return &BatchError{BatchNumber: batch.header.BatchNumber, FieldName: "StandardEntryClassCode", Msg: msg}
+	}
+
+	if err := batch.isEntrySEC(); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Create builds the batch sequence numbers and batch control. Additional creation
+func (batch *BatchPOP) Create() error {
+	// generates sequence numbers and batch control
+	if err := batch.build(); err != nil {
+		return err
+	}
+
+	if err := batch.Validate(); err != nil {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,150 @@
+// Copyright 2017 The ACH Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package ach
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func mockBatchPOPHeader() *BatchHeader {
+	bh := NewBatchHeader()
+	bh.ServiceClassCode = 225
+	bh.StandardEntryClassCode = "POP"
+	bh.CompanyName = "Your Company, inc"
+	bh.CompanyIdentification = "123456789"
+	bh.CompanyEntryDescription = "PURCHASE"
+	bh.ODFIIdentification = 6200001
+	bh.EffectiveEntryDate = time.Now().AddDate(0, 0, 1)
+	return bh
+}
+
+func mockPOPEntryDetail() *EntryDetail {
+	entry := NewEntryDetail()
+	entry.TransactionCode = 27
+	entry.SetRDFI(9101298)
+	entry.DFIAccountNumber = "744-5678-99"
+	entry.Amount = 12500
+	entry.SetPOPCheckSerialNumber("123456")
+	entry.SetPOPTerminalCity("PHIL")
+	entry.SetPOPTerminalState("PA")
+	entry.IndividualName = "Wade Arnold"
+	entry.TraceNumber = 123456789
+	return entry
+}
+
+func mockBatchPOP() *BatchPOP {
+	mockBatch := NewBatchPOP()
+	mockBatch.SetHeader(mockBatchPOPHeader())
+	mockBatch.AddEntry(mockPOPEntryDetail())
+	if err := mockBatch.Create(); err != nil {
+		panic(err)
+	}
+	return mockBatch
+}
+
+func TestBatchPOPFields(t *testing.T) {
+	entry := mockBatchPOP().GetEntries()[0]
+	if entry.IdentificationNumberField() != "123456   PHILPA" {
+		t.Errorf("IdentificationNumber got: %q", entry.IdentificationNumberField())
+	}
+	if entry.POPCheckSerialNumberField() != "123456   " || entry.POPTerminalCityField() != "PHIL" || entry.POPTerminalStateField() != "PA" {
+		t.Errorf("POP fields got: %q %q %q", entry.POPCheckSerialNumberField(), entry.POPTerminalCityField(), entry.POPTerminalStateField())
+	}
+}
+
+func TestBatchPOPEntryFields(t *testing.T) {
+	tests := []struct {
+		fieldName string
+		set       func(*EntryDetail)
+	}{
+		{"POPCheckSerialNumber", func(ed *EntryDetail) { ed.SetPOPCheckSerialNumber("") }},
+		{"POPTerminal", func(ed *EntryDetail) { ed.SetPOPTerminalCity("") }},
+		{"POPTerminal", func(ed *EntryDetail) { ed.SetPOPTerminalState("P1") }},
+		{"Amount", func(ed *EntryDetail) { ed.Amount = 2500001 }},
+		{"TransactionCode", func(ed *EntryDetail) { ed.TransactionCode = 22 }},
+	}
+	for _, test := range tests {
+		mockBatch := mockBatchPOP()
+		test.set(mockBatch.GetEntries()[0])
+		if err := mockBatch.Create(); err != nil {
+			if e, ok := err.(*BatchError); ok {
+				if e.FieldName != test.fieldName {
+					t.Errorf("%T: %s", err, err)
+				}
+			} else {
+				t.Errorf("%T: %s", err, err)
+			}
+		} else {
+			t.Errorf("expected %v error", test.fieldName)
+		}
+	}
+}
+
+// POP entries do not have addenda
+func TestBatchPOPAddenda(t *testing.T) {
+	mockBatch := mockBatchPOP()
+	mockBatch.GetEntries()[0].AddAddenda(mockAddenda())
+	if err := mockBatch.Create(); err != nil {
+		if e, ok := err.(*BatchError); ok {
+			if e.FieldName != "AddendaCount" {
+				t.Errorf("%T: %s", err, err)
+			}
+		} else {
+			t.Errorf("%T: %s", err, err)
+		}
+	} else {
+		t.Error("expected error for POP addenda")
+	}
+}
+
+func TestBatchPOPSEC(t *testing.T) {
+	mockBatch := mockBatchPOP()
+	mockBatch.GetHeader().StandardEntryClassCode = "RCK"
+	if err := mockBatch.Validate(); err != nil {
+		if e, ok := err.(*BatchError); ok {
+			if e.FieldName != "StandardEntryClassCode" {
+				t.Errorf("%T: %s", err, err)
+			}
+		} else {
+			t.Errorf("%T: %s", err, err)
+		}
+	} else {
+		t.Error("expected error for SEC code")
+	}
+}
+
+func TestBatchPOPReadWrite(t *testing.T) {
+	file := NewFile().SetHeader(mockFileHeader())
+	file.AddBatch(mockBatchPOP())
+	if err := file.Create(); err != nil {
+		t.Fatalf("%T: %s", err, err)
+	}
+	b := &bytes.Buffer{}
+	if err := NewWriter(b).WriteAll([]*File{file}); err != nil {
+		t.Fatalf("%T: %s", err, err)
+	}
+	read, err := NewReader(strings.NewReader(b.String())).Read()
+	if err != nil {
+		t.Fatalf("%T: %s", err, err)
+	}
+	batch, ok := read.Batches[0].(*BatchPOP)
+	if !ok {
+		t.Fatalf("expected *BatchPOP got: %T", read.Batches[0])
+	}
+	entry := batch.GetEntries()[0]
+	if entry.POPCheckSerialNumberField() != "123456   " || entry.POPTerminalCityField() != "PHIL" || entry.POPTerminalStateField() != "PA" {
+		t.Errorf("POP fields got: %q %q %q", entry.POPCheckSerialNumberField(), entry.POPTerminalCityField(), entry.POPTerminalStateField())
+	}
+	out := &bytes.Buffer{}
+	if err := NewWriter(out).WriteAll([]*File{&read}); err != nil {
+		t.Fatalf("%T: %s", err, err)
+	}
+	if out.String() != b.String() {
+		t.Errorf("POP file does not round trip\ngot:\n%s\nwant:\n%s", out, b)
+	}
+}
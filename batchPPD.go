@@ -43,7 +43,9 @@ func (batch *BatchPPD) Validate() error {
 	}
 
 	// Add type specific validation.
-	// ...
+	if err := batch.isEntrySEC(); err != nil {
+		return err
+	}
 	return nil
 }
 
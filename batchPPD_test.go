@@ -5,6 +5,9 @@
 package ach
 
 import (
+	"errors"
+	"fmt"
+	"strings"
 	"testing"
 	"time"
 )
@@ -29,14 +32,13 @@ func TestBatchError(t *testing.T) {
 func TestBatchServiceClassCodeEquality(t *testing.T) {
 	mockBatch := mockBatchPPD()
 	mockBatch.GetControl().ServiceClassCode = 225
-	if err := mockBatch.Validate(); err != nil {
-		if e, ok := err.(*BatchError); ok {
-			if e.FieldName != "ServiceClassCode" {
-				t.Errorf("%T: %s", err, err)
-			}
-		} else {
+	err := mockBatch.Validate()
+	if e, ok := err.(*BatchError); ok {
+		if e.FieldName != "ServiceClassCode" || e.Msg != fmt.Sprintf(msgBatchHeaderControlEquality, 220, 225) {
 			t.Errorf("%T: %s", err, err)
 		}
+	} else {
+		t.Errorf("expected BatchError got: %v", err)
 	}
 }
 
@@ -206,6 +208,43 @@ func TestBatchDNEMismatch(t *testing.T) {
 	}
 }
 
+// Zero amount prenotes are valid with SetAllowZeroAmountPrenotes and live entries still need an amount
+func TestBatchAllowZeroAmountPrenotes(t *testing.T) {
+	batches := []interface {
+		Batcher
+		SetAllowZeroAmountPrenotes(bool)
+	}{mockBatchPPD(), mockBatchWEB(), mockBatchCCD(), mockBatchCTX()}
+	for _, mockBatch := range batches {
+		sec := mockBatch.GetHeader().StandardEntryClassCode
+		entry := mockBatch.GetEntries()[0]
+		entry.TransactionCode = 23
+		entry.Amount = 0
+		if err := mockBatch.Create(); err == nil {
+			t.Errorf("%s expected error for a zero amount prenote without SetAllowZeroAmountPrenotes", sec)
+		}
+		mockBatch.SetAllowZeroAmountPrenotes(true)
+		for _, code := range []int{23, 28, 33, 38} {
+			entry.TransactionCode = code
+			if err := mockBatch.Create(); err != nil {
+				t.Errorf("%s prenote %d: %s", sec, code, err)
+			}
+		}
+
+		entry.TransactionCode = 22
+		if err := mockBatch.Create(); err != nil {
+			if e, ok := err.(*BatchError); ok {
+				if e.FieldName != "Amount" {
+					t.Errorf("%T: %s", err, err)
+				}
+			} else {
+				t.Errorf("%T: %s", err, err)
+			}
+		} else {
+			t.Errorf("%s expected error for a zero amount live entry", sec)
+		}
+	}
+}
+
 func TestBatchTraceNumberNotODFI(t *testing.T) {
 	mockBatch := mockBatchPPD()
 	mockBatch.GetEntries()[0].setTraceNumber(12345678, 1)
@@ -345,3 +384,233 @@ func TestBatchBuild(t *testing.T) {
 		t.Errorf("%T: %s", err, err)
 	}
 }
+
+func TestBatchEffectiveEntryDate(t *testing.T) {
+	mockBatch := mockBatchPPD()
+	mockBatch.GetHeader().EffectiveEntryDate = time.Time{}
+	if _, err := mockBatch.EffectiveEntryDate(); err != nil {
+		if e, ok := err.(*BatchError); ok {
+			if e.FieldName != "EffectiveEntryDate" {
+				t.Errorf("%T: %s", err, err)
+			}
+		} else {
+			t.Errorf("%T: %s", err, err)
+		}
+	} else {
+		t.Error("expected error for missing EffectiveEntryDate")
+	}
+
+	now := time.Now()
+	mockBatch.GetHeader().EffectiveEntryDate = now
+	d, err := mockBatch.EffectiveEntryDate()
+	if err != nil {
+		t.Errorf("%T: %s", err, err)
+	}
+	if !d.Equal(now) {
+		t.Errorf("EffectiveEntryDate Expected %v got: %v", now, d)
+	}
+}
+
+// TestBatchValidateEffectiveEntryDate a forward batch without an EffectiveEntryDate is invalid
+func TestBatchValidateEffectiveEntryDate(t *testing.T) {
+	file := mockFilePPD()
+	file.Batches[0].GetHeader().EffectiveEntryDate = time.Time{}
+	for _, err := range []error{file.Batches[0].Validate(), file.ValidateWith(&ValidateOpts{})} {
+		if e, ok := err.(*BatchError); ok {
+			if e.FieldName != "EffectiveEntryDate" {
+				t.Errorf("%T: %s", err, err)
+			}
+		} else {
+			t.Errorf("expected BatchError got: %v", err)
+		}
+	}
+}
+
+func TestBatchNetAmount(t *testing.T) {
+	mockBatch := NewBatchPPD()
+	mockBatch.SetHeader(mockBatchHeader())
+	e1 := mockEntryDetail()
+	e1.TransactionCode = 22
+	e1.Amount = 500
+	e2 := mockEntryDetail()
+	e2.TransactionCode = 37
+	e2.Amount = 800
+	mockBatch.AddEntry(e1)
+	mockBatch.AddEntry(e2)
+	if mockBatch.NetAmount() != -300 {
+		t.Errorf("NetAmount Expected -300 got: %v", mockBatch.NetAmount())
+	}
+}
+
+func TestBatchEntryAddendaCount(t *testing.T) {
+	mockBatch := NewBatchPPD()
+	mockBatch.SetHeader(mockBatchHeader())
+	e1 := mockEntryDetail()
+	e1.AddAddenda(mockAddenda())
+	mockBatch.AddEntry(e1)
+	mockBatch.AddEntry(mockEntryDetail())
+	if mockBatch.EntryAddendaCount() != 3 {
+		t.Errorf("EntryAddendaCount Expected 3 got: %v", mockBatch.EntryAddendaCount())
+	}
+	if err := mockBatch.Create(); err != nil {
+		t.Fatalf("%T: %s", err, err)
+	}
+	if mockBatch.GetControl().EntryAddendaCount != mockBatch.EntryAddendaCount() {
+		t.Errorf("EntryAddendaCount Expected %v got: %v", mockBatch.GetControl().EntryAddendaCount, mockBatch.EntryAddendaCount())
+	}
+}
+
+func TestBatchAsFieldError(t *testing.T) {
+	mockBatch := NewBatchPPD()
+	mockBatch.SetHeader(mockBatchHeader())
+	for i := 0; i < 3; i++ {
+		mockBatch.AddEntry(mockEntryDetail())
+	}
+	if err := mockBatch.Create(); err != nil {
+		t.Fatalf("%T: %s", err, err)
+	}
+	mockBatch.GetEntries()[1].TransactionCode = 99
+	loc, ok := AsFieldError(mockBatch.Validate())
+	if !ok {
+		t.Fatal("AsFieldError expected a location")
+	}
+	if loc.FieldName != "TransactionCode" || loc.EntryIndex != 1 || loc.BatchIndex != -1 || loc.LineNumber != 0 {
+		t.Errorf("unexpected location %+v", loc)
+	}
+
+	mockBatch.GetEntries()[1].TransactionCode = 22
+	mockBatch.GetControl().TotalCreditEntryDollarAmount = 1
+	loc, ok = AsFieldError(mockBatch.Validate())
+	if !ok || loc.FieldName != "TotalCreditEntryDollarAmount" || loc.EntryIndex != -1 {
+		t.Errorf("unexpected location %+v", loc)
+	}
+
+	mockBatch.GetEntries()[2].AddAddenda(mockAddenda())
+	if err := mockBatch.Create(); err != nil {
+		t.Fatalf("%T: %s", err, err)
+	}
+	mockBatch.GetEntries()[2].Addendum[0].PaymentRelatedInformation = "\x00"
+	loc, ok = AsFieldError(mockBatch.Validate())
+	if !ok || loc.FieldName != "PaymentRelatedInformation" || loc.EntryIndex != 2 {
+		t.Errorf("unexpected location %+v", loc)
+	}
+
+	if _, ok := AsFieldError(errors.New("mock")); ok {
+		t.Error("AsFieldError expected no location")
+	}
+}
+
+func TestBatchIdentificationNumberValidator(t *testing.T) {
+	mockBatch := mockBatchPPD()
+	mockBatch.GetEntries()[0].IdentificationNumber = "0123456789ABCDEF"
+	if err := mockBatch.Validate(); err != nil {
+		if e, ok := err.(*BatchError); ok {
+			if e.FieldName != "IdentificationNumber" {
+				t.Errorf("%T: %s", err, err)
+			}
+		} else {
+			t.Errorf("%T: %s", err, err)
+		}
+	} else {
+		t.Error("expected error for IdentificationNumber longer than 15 characters")
+	}
+
+	mockBatch.SetIdentificationNumberValidator(func(s string) error {
+		if !strings.HasPrefix(s, "INV") {
+			return errors.New("does not start with INV")
+		}
+		return nil
+	})
+	mockBatch.GetEntries()[0].IdentificationNumber = "123"
+	if err := mockBatch.Validate(); err != nil {
+		if e, ok := err.(*BatchError); ok {
+			if e.FieldName != "IdentificationNumber" {
+				t.Errorf("%T: %s", err, err)
+			}
+		} else {
+			t.Errorf("%T: %s", err, err)
+		}
+	} else {
+		t.Error("expected error from IdentificationNumber validator")
+	}
+	mockBatch.GetEntries()[0].IdentificationNumber = "INV123"
+	if err := mockBatch.Validate(); err != nil {
+		t.Errorf("%T: %s", err, err)
+	}
+}
+
+// Zero dollar remittance transaction codes are only allowed in CCD and CTX batches
+func TestBatchPPDTransactionCodeSEC(t *testing.T) {
+	mockBatch := mockBatchPPD()
+	mockBatch.AddEntry(mockEntryDetail())
+	mockBatch.GetEntries()[1].TransactionCode = 24
+	mockBatch.GetEntries()[1].Amount = 0
+	err := mockBatch.Create()
+	if e, ok := err.(*BatchError); ok {
+		if e.FieldName != "TransactionCode" {
+			t.Errorf("%T: %s", err, err)
+		}
+	} else {
+		t.Errorf("expected BatchError got: %v", err)
+	}
+	if loc, ok := AsFieldError(err); !ok || loc.EntryIndex != 1 {
+		t.Errorf("AsFieldError %+v", loc)
+	}
+}
+
+func TestBatchRequireSegregatedPrenotes(t *testing.T) {
+	mockBatch := mockBatchPPD()
+	entry := mockEntryDetail()
+	entry.TransactionCode = 23
+	entry.Amount = 0
+	mockBatch.AddEntry(entry)
+	mockBatch.GetHeader().OriginatorStatusCode = 2
+	if err := mockBatch.Create(); err != nil {
+		t.Fatalf("%T: %s", err, err)
+	}
+	mockBatch.SetRequireSegregatedPrenotes(true)
+	err := mockBatch.Create()
+	if e, ok := err.(*BatchError); ok {
+		if e.FieldName != "TransactionCode" {
+			t.Errorf("%T: %s", err, err)
+		}
+	} else {
+		t.Errorf("expected BatchError got: %v", err)
+	}
+	if loc, ok := AsFieldError(err); !ok || loc.EntryIndex != 1 {
+		t.Errorf("AsFieldError %+v", loc)
+	}
+	mockBatch.GetEntries()[0].TransactionCode = 23
+	mockBatch.GetEntries()[0].Amount = 0
+	if err := mockBatch.Create(); err != nil {
+		t.Errorf("%T: %s", err, err)
+	}
+}
+
+func TestBatchRequireUniformCategory(t *testing.T) {
+	mockBatch := mockBatchPPD()
+	entry := mockEntryDetail()
+	entry.Category = CategoryReturn
+	mockBatch.AddEntry(entry)
+	if err := mockBatch.Create(); err != nil {
+		t.Fatalf("%T: %s", err, err)
+	}
+	mockBatch.SetRequireUniformCategory(true)
+	err := mockBatch.Validate()
+	if e, ok := err.(*BatchError); ok {
+		if e.FieldName != "Category" || e.BatchNumber != mockBatch.GetHeader().BatchNumber {
+			t.Errorf("%T: %s", err, err)
+		}
+	} else {
+		t.Errorf("expected BatchError got: %v", err)
+	}
+	mockBatch.GetEntries()[0].Category = CategoryReturn
+	if err := mockBatch.Validate(); err != nil {
+		t.Errorf("%T: %s", err, err)
+	}
+	mockBatch.SetRequireUniformCategory(false)
+	mockBatch.GetEntries()[0].Category = CategoryForward
+	if err := mockBatch.Validate(); err != nil {
+		t.Errorf("%T: %s", err, err)
+	}
+}
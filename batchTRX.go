@@ -0,0 +1,79 @@
+// Copyright 2017 The ACH Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package ach
+
+import (
+	"fmt"
+)
+
+// BatchTRX creates a batch file that handles SEC payment type TRX.
+// Check Truncation Entries Exchange. Identifies a debit Entry that exchanges the data of
+// truncated checks between financial institutions. Like CTX the check data is carried in
+// up to 9,999 addenda records per entry.
+type BatchTRX struct {
+	batch
+}
+
+// NewBatchTRX returns a *BatchTRX
+func NewBatchTRX(params ...BatchParam) *BatchTRX {
+	batch := new(BatchTRX)
+	batch.SetControl(NewBatchControl())
+
+	if len(params) > 0 {
+		bh := NewBatchHeader(params[0])
+		bh.StandardEntryClassCode = trx
+		batch.SetHeader(bh)
+		return batch
+	}
+	bh := NewBatchHeader()
+	bh.StandardEntryClassCode = trx
+	batch.SetHeader(bh)
+	return batch
+}
+
+// Validate ensures the batch meets NACHA rules specific to this batch type.
+func (batch *BatchTRX) Validate() error {
+	// basic verification of the batch before we validate specific rules.
+	if err := batch.verify(); err != nil {
+		return err
+	}
+	// Add configuration based validation for this type.
+	// TRX can have up to 9,999 addenda per entry record
+	if err := batch.isAddendaCount(9999); err != nil {
+		return err
+	}
+	if err := batch.isTypeCode("05"); err != nil {
+		return err
+	}
+
+	// Add type specific validation.
+	if batch.header.StandardEntryClassCode != trx {
+		msg := fmt.Sprintf(msgBatchSECType, batch.header.StandardEntryClassCode, trx)
+		return &BatchError{BatchNumber: batch.header.BatchNumber, FieldName: "StandardEntryClassCode", Msg: msg}
+	}
+
+	if err := batch.isEntrySEC(); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Create builds the batch sequence numbers and batch control. Additional creation
+func (batch *BatchTRX) Create() error {
+	// Number of addenda records in each entry is set before it is validated
+	for _, entry := range batch.entries {
+		entry.SetCATXAddendaRecords(len(entry.Addendum))
+	}
+	// generates sequence numbers and batch control
+	if err := batch.build(); err != nil {
+		return err
+	}
+
+	if err := batch.Validate(); err != nil {
+		return err
+	}
+	return nil
+}
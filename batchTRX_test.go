@@ -0,0 +1,158 @@
+// Copyright 2017 The ACH Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package ach
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func mockBatchTRXHeader() *BatchHeader {
+	bh := NewBatchHeader()
+	bh.ServiceClassCode = 225
+	bh.StandardEntryClassCode = "TRX"
+	bh.CompanyName = "Your Company, inc"
+	bh.CompanyIdentification = "123456789"
+	bh.CompanyEntryDescription = "CHECK TRUNC"
+	bh.ODFIIdentification = 6200001
+	bh.EffectiveEntryDate = time.Now().AddDate(0, 0, 1)
+	return bh
+}
+
+func mockTRXEntryDetail() *EntryDetail {
+	entry := NewEntryDetail()
+	entry.TransactionCode = 27
+	entry.SetRDFI(9101298)
+	entry.DFIAccountNumber = "744-5678-99"
+	entry.Amount = 5000000
+	entry.IdentificationNumber = "location #23"
+	entry.SetCATXReceivingCompany("Best Co. #23")
+	entry.TraceNumber = 123456789
+	return entry
+}
+
+func mockBatchTRX() *BatchTRX {
+	mockBatch := NewBatchTRX()
+	mockBatch.SetHeader(mockBatchTRXHeader())
+	mockBatch.AddEntry(mockTRXEntryDetail())
+	mockBatch.GetEntries()[0].AddAddenda(mockAddenda())
+	mockBatch.GetEntries()[0].AddAddenda(mockAddenda())
+	if err := mockBatch.Create(); err != nil {
+		panic(err)
+	}
+	return mockBatch
+}
+
+// Create sets the number of addenda records on each entry
+func TestBatchTRXCreate(t *testing.T) {
+	mockBatch := mockBatchTRX()
+	entry := mockBatch.GetEntries()[0]
+	if entry.CATXAddendaRecordsField() != "0002" {
+		t.Errorf("CATXAddendaRecords Expected '0002' got: %v", entry.CATXAddendaRecordsField())
+	}
+}
+
+// The number of addenda records in the entry must match the addendum
+func TestBatchTRXAddendaRecords(t *testing.T) {
+	mockBatch := mockBatchTRX()
+	mockBatch.GetEntries()[0].SetCATXAddendaRecords(5)
+	if err := mockBatch.Validate(); err != nil {
+		if e, ok := err.(*BatchError); ok {
+			if e.FieldName != "CATXAddendaRecords" {
+				t.Errorf("%T: %s", err, err)
+			}
+		} else {
+			t.Errorf("%T: %s", err, err)
+		}
+	} else {
+		t.Error("expected error for CATXAddendaRecords mismatch")
+	}
+}
+
+// TRX entries can have up to 9999 addenda
+func TestBatchTRXAddendaCount(t *testing.T) {
+	mockBatch := mockBatchTRX()
+	entry := mockBatch.GetEntries()[0]
+	for len(entry.Addendum) < 10000 {
+		entry.AddAddenda(mockAddenda())
+	}
+	if err := mockBatch.Create(); err != nil {
+		if e, ok := err.(*BatchError); ok {
+			if e.FieldName != "AddendaCount" {
+				t.Errorf("%T: %s", err, err)
+			}
+		} else {
+			t.Errorf("%T: %s", err, err)
+		}
+	} else {
+		t.Error("expected error for 10000 addenda")
+	}
+}
+
+func TestBatchTRXCredit(t *testing.T) {
+	mockBatch := mockBatchTRX()
+	mockBatch.GetEntries()[0].TransactionCode = 22
+	if err := mockBatch.Create(); err != nil {
+		if e, ok := err.(*BatchError); ok {
+			if e.FieldName != "TransactionCode" {
+				t.Errorf("%T: %s", err, err)
+			}
+		} else {
+			t.Errorf("%T: %s", err, err)
+		}
+	} else {
+		t.Error("expected error for a credit entry")
+	}
+}
+
+func TestBatchTRXSEC(t *testing.T) {
+	mockBatch := mockBatchTRX()
+	mockBatch.GetHeader().StandardEntryClassCode = "RCK"
+	if err := mockBatch.Validate(); err != nil {
+		if e, ok := err.(*BatchError); ok {
+			if e.FieldName != "StandardEntryClassCode" {
+				t.Errorf("%T: %s", err, err)
+			}
+		} else {
+			t.Errorf("%T: %s", err, err)
+		}
+	} else {
+		t.Error("expected error for SEC code")
+	}
+}
+
+func TestBatchTRXReadWrite(t *testing.T) {
+	file := NewFile().SetHeader(mockFileHeader())
+	file.AddBatch(mockBatchTRX())
+	if err := file.Create(); err != nil {
+		t.Fatalf("%T: %s", err, err)
+	}
+	b := &bytes.Buffer{}
+	w := NewWriter(b)
+	if err := w.WriteAll([]*File{file}); err != nil {
+		t.Fatalf("%T: %s", err, err)
+	}
+	r := NewReader(strings.NewReader(b.String()))
+	read, err := r.Read()
+	if err != nil {
+		t.Fatalf("%T: %s", err, err)
+	}
+	batch, ok := read.Batches[0].(*BatchTRX)
+	if !ok {
+		t.Fatalf("expected *BatchTRX got: %T", read.Batches[0])
+	}
+	if len(batch.GetEntries()[0].Addendum) != 2 {
+		t.Errorf("expected 2 addenda got: %d", len(batch.GetEntries()[0].Addendum))
+	}
+	out := &bytes.Buffer{}
+	if err := NewWriter(out).WriteAll([]*File{&read}); err != nil {
+		t.Fatalf("%T: %s", err, err)
+	}
+	if out.String() != b.String() {
+		t.Errorf("TRX file does not round trip\ngot:\n%s\nwant:\n%s", out, b)
+	}
+}
@@ -1,6 +1,9 @@
 package ach
 
-import "testing"
+import (
+	"testing"
+	"time"
+)
 
 func mockBatchWEBHeader() *BatchHeader {
 	bh := NewBatchHeader()
@@ -10,6 +13,7 @@ func mockBatchWEBHeader() *BatchHeader {
 	bh.CompanyIdentification = "123456789"
 	bh.CompanyEntryDescription = "Online Order"
 	bh.ODFIIdentification = 6200001
+	bh.EffectiveEntryDate = time.Now().AddDate(0, 0, 1)
 	return bh
 }
 
@@ -2,7 +2,6 @@ package ach
 
 import (
 	"fmt"
-	"strings"
 )
 
 // BatchWEB creates a batch file that handles SEC payment type WEB.
@@ -50,7 +49,7 @@ func (batch *BatchWEB) Validate() error {
 		return &BatchError{BatchNumber: batch.header.BatchNumber, FieldName: "StandardEntryClassCode", Msg: msg}
 	}
 
-	if err := batch.isPaymentTypeCode(); err != nil {
+	if err := batch.isEntrySEC(); err != nil {
 		return err
 	}
 
@@ -69,16 +68,3 @@ func (batch *BatchWEB) Create() error {
 	}
 	return nil
 }
-
-// isPaymentTypeCode checks that the Entry detail records have either:
-// "R" For a recurring WEB Entry
-// "S" For a Single-Entry WEB Entry
-func (batch *BatchWEB) isPaymentTypeCode() error {
-	for _, entry := range batch.entries {
-		if !strings.Contains(strings.ToUpper(entry.PaymentType()), "S") && !strings.Contains(strings.ToUpper(entry.PaymentType()), "R") {
-			msg := fmt.Sprintf(msgBatchWebPaymentType, entry.PaymentType())
-			return &BatchError{BatchNumber: batch.header.BatchNumber, FieldName: "PaymentType", Msg: msg}
-		}
-	}
-	return nil
-}
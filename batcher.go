@@ -2,6 +2,7 @@ package ach
 
 import (
 	"fmt"
+	"time"
 )
 
 // Batcher abstract the different ACH batch types that can exist in a file.
@@ -19,6 +20,9 @@ type Batcher interface {
 	SetControl(*BatchControl)
 	GetEntries() []*EntryDetail
 	AddEntry(*EntryDetail)
+	EffectiveEntryDate() (time.Time, error)
+	EntryAddendaCount() int
+	WithOffset(*Offset) error
 	Create() error
 	Validate() error
 }
@@ -28,6 +32,10 @@ type BatchError struct {
 	BatchNumber int
 	FieldName   string
 	Msg         string
+	// batchIndex is one more than the index of the batch in the file the error is for, or zero
+	batchIndex int
+	// entryIndex is one more than the index of the entry the error is for, or zero
+	entryIndex int
 }
 
 func (e *BatchError) Error() string {
@@ -67,6 +75,7 @@ var (
 	msgBatchFieldInclusion            = "%v is a required field "
 	// specific messages for error
 	msgBatchOriginatorDNE         = "%v is not “2” for DNE with entry transaction code of 23 or 33"
+	msgBatchZeroAmount            = "is zero for transaction code %v which is not a prenote"
 	msgBatchTraceNumberNotODFI    = "%v in header does not match entry trace number %v"
 	msgBatchAddendaIndicator      = "is 0 but found addenda record(s)"
 	msgBatchAddendaTraceNumber    = "%v does not match proceeding entry detail trace number %v"
@@ -75,4 +84,9 @@ var (
 	msgBatchTransactionCodeCredit = "%v a credit is not allowed"
 	msgBatchSECType               = "header SEC type code %v for batch type %v"
 	msgBatchTypeCode              = "%v found in addenda and expecting %v for batch type %v"
+	msgBatchEffectiveEntryDate    = "is not set in the batch header"
+	msgBatchFieldLength           = "%v is longer than %d characters"
+	msgBatchPrenoteMixed          = "prenote %v and live %v entries are in the same batch"
+	msgBatchCategoryMixed         = "forward %v and return %v entries are in the same batch"
+	msgBatchOffsetSEC             = "%v batches can not be offset, only PPD, CCD and WEB batches"
 )
@@ -40,8 +40,30 @@ func (c *converters) parseSimpleTime(s string) time.Time {
 	return t
 }
 
+// formatDollars returns cents as a US dollar string with thousands separators. For example "$1,234.56"
+func (c *converters) formatDollars(cents int) string {
+	sign := ""
+	if cents < 0 {
+		sign = "-"
+		cents = -cents
+	}
+	dollars := strconv.Itoa(cents / 100)
+	for i := len(dollars) - 3; i > 0; i -= 3 {
+		dollars = dollars[:i] + "," + dollars[i:]
+	}
+	return sign + "$" + dollars + "." + c.numericField(cents%100, 2)
+}
+
 //func (v *Converters) numericField()
 
+// padRecord space fills a record shorter than RecordLength so that it can be parsed
+func (c *converters) padRecord(record string) string {
+	if len(record) < RecordLength {
+		return record + strings.Repeat(" ", RecordLength-len(record))
+	}
+	return record
+}
+
 // alphaField Alphanumeric and Alphabetic fields are left-justified and space filled.
 func (c *converters) alphaField(s string, max uint) string {
 	ln := uint(len(s))
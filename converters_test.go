@@ -50,3 +50,20 @@ func TestParseNumField(t *testing.T) {
 		t.Errorf("Right justified zero got: '%v'", result)
 	}
 }
+
+// TestFormatDollars ensures cents are formatted as dollars with thousands separators
+func TestFormatDollars(t *testing.T) {
+	c := converters{}
+	tests := map[int]string{
+		0:          "$0.00",
+		5:          "$0.05",
+		123456:     "$1,234.56",
+		100000000:  "$1,000,000.00",
+		-123456789: "-$1,234,567.89",
+	}
+	for cents, want := range tests {
+		if got := c.formatDollars(cents); got != want {
+			t.Errorf("formatDollars(%d) got: '%v' want: '%v'", cents, got, want)
+		}
+	}
+}
@@ -0,0 +1,116 @@
+// Copyright 2017 The ACH Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package ach
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// DelimitedColumns maps a record type to the NACHA field width of each delimited column
+// in that record. Addenda layouts are selected by record type and addenda type code
+// ("705", "798", "799") before falling back to the record type ("7").
+type DelimitedColumns map[string][]int
+
+// DefaultDelimitedColumns has one column for every NACHA field of each record type.
+var DefaultDelimitedColumns = DelimitedColumns{
+	fileHeaderPos:   {1, 2, 10, 10, 6, 4, 1, 3, 2, 1, 23, 23, 8},
+	batchHeaderPos:  {1, 3, 16, 20, 10, 3, 10, 6, 6, 3, 1, 8, 7},
+	entryDetailPos:  {1, 2, 8, 1, 17, 10, 15, 22, 2, 1, 15},
+	entryAddendaPos: {1, 2, 80, 4, 7},
+	"705":           {1, 2, 80, 4, 7},
+	"798":           {1, 2, 3, 15, 6, 8, 29, 15, 15},
+	"799":           {1, 2, 3, 15, 6, 8, 44, 15},
+	batchControlPos: {1, 3, 6, 10, 12, 12, 10, 19, 6, 8, 7},
+	fileControlPos:  {1, 6, 6, 8, 10, 12, 12, 39},
+}
+
+// Errors specific to reading delimited files
+var (
+	msgDelimitedColumnCount = "found %d columns expecting %d"
+	msgDelimitedColumnWidth = "column %d is longer than %d characters"
+)
+
+// DelimitedReader reads files where each record is a line of delimited columns rather than
+// fixed width NACHA fields. It is an adapter for partner exports that can not produce NACHA
+// files. Each line is converted to a NACHA record and parsed by Reader.
+type DelimitedReader struct {
+	// Columns defines the field width of each column by record type.
+	Columns DelimitedColumns
+	// scanner handles the io.Reader sent to be parsed.
+	scanner *bufio.Scanner
+	// delim separates the columns in a line
+	delim string
+	// line number of the file being parsed
+	lineNum int
+}
+
+// NewDelimitedReader returns a new DelimitedReader that reads from r using delim to
+// separate columns and DefaultDelimitedColumns to map columns to record fields.
+func NewDelimitedReader(r io.Reader, delim rune) *DelimitedReader {
+	return &DelimitedReader{
+		Columns: DefaultDelimitedColumns,
+		scanner: bufio.NewScanner(r),
+		delim:   string(delim),
+	}
+}
+
+// Read converts each delimited line to a NACHA record and parses the records into a File.
+func (r *DelimitedReader) Read() (*File, error) {
+	var records []string
+	r.lineNum = 0
+	for r.scanner.Scan() {
+		r.lineNum++
+		line := r.scanner.Text()
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		record, err := r.record(strings.Split(line, r.delim))
+		if err != nil {
+			return nil, &ParseError{Line: r.lineNum, Err: err}
+		}
+		records = append(records, record)
+	}
+	if err := r.scanner.Err(); err != nil {
+		return nil, err
+	}
+	file, err := NewReader(strings.NewReader(strings.Join(records, "\n"))).Read()
+	if err != nil {
+		return nil, err
+	}
+	return &file, nil
+}
+
+// record builds a NACHA record from the columns of a line using the column widths of the record type
+func (r *DelimitedReader) record(columns []string) (string, error) {
+	recordType := strings.TrimSpace(columns[0])
+	widths, ok := r.Columns[recordType]
+	if len(columns) > 1 {
+		if w, found := r.Columns[recordType+strings.TrimSpace(columns[1])]; found {
+			widths, ok = w, true
+		}
+	}
+	if !ok {
+		msg := fmt.Sprintf(msgUnknownRecordType, recordType)
+		return "", &FileError{FieldName: "recordType", Value: recordType, Msg: msg}
+	}
+	if len(columns) != len(widths) {
+		msg := fmt.Sprintf(msgDelimitedColumnCount, len(columns), len(widths))
+		return "", &FileError{FieldName: "Columns", Value: strconv.Itoa(len(columns)), Msg: msg}
+	}
+	var c converters
+	record := ""
+	for i, column := range columns {
+		if len(column) > widths[i] {
+			msg := fmt.Sprintf(msgDelimitedColumnWidth, i+1, widths[i])
+			return "", &FileError{FieldName: "Columns", Value: column, Msg: msg}
+		}
+		record += c.alphaField(column, uint(widths[i]))
+	}
+	return record, nil
+}
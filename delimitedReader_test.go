@@ -0,0 +1,77 @@
+// Copyright 2017 The ACH Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package ach
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+// mockDelimitedFile converts a NACHA file to tab delimited columns using DefaultDelimitedColumns
+func mockDelimitedFile(t *testing.T, file *File) string {
+	buf := new(bytes.Buffer)
+	w := NewWriter(buf)
+	if err := w.Write(file); err != nil {
+		t.Fatalf("%T: %s", err, err)
+	}
+	w.Flush()
+	var lines []string
+	for _, record := range strings.Split(buf.String(), "\n") {
+		if record == "" || record == strings.Repeat("9", RecordLength) {
+			continue
+		}
+		widths := DefaultDelimitedColumns[record[:1]]
+		var columns []string
+		pos := 0
+		for _, width := range widths {
+			columns = append(columns, strings.TrimSpace(record[pos:pos+width]))
+			pos += width
+		}
+		lines = append(lines, strings.Join(columns, "\t"))
+	}
+	return strings.Join(lines, "\n")
+}
+
+func TestDelimitedReaderRead(t *testing.T) {
+	mockFile := mockFilePPD()
+	mockFile.Batches[0].GetEntries()[0].AddAddenda(mockAddenda())
+	mockFile.Batches[0].Create()
+	mockFile.Create()
+	r := NewDelimitedReader(strings.NewReader(mockDelimitedFile(t, mockFile)), '\t')
+	file, err := r.Read()
+	if err != nil {
+		t.Fatalf("%T: %s", err, err)
+	}
+	if err := file.Validate(); err != nil {
+		t.Errorf("%T: %s", err, err)
+	}
+	if file.Header.String() != mockFile.Header.String() {
+		t.Errorf("FileHeader Expected %v got: %v", mockFile.Header.String(), file.Header.String())
+	}
+	entry := file.Batches[0].GetEntries()[0]
+	if entry.String() != mockFile.Batches[0].GetEntries()[0].String() {
+		t.Errorf("EntryDetail Expected %v got: %v", mockFile.Batches[0].GetEntries()[0].String(), entry.String())
+	}
+	if len(entry.Addendum) != 1 {
+		t.Errorf("Addendum Expected 1 got: %v", len(entry.Addendum))
+	}
+}
+
+func TestDelimitedReaderColumnCount(t *testing.T) {
+	r := NewDelimitedReader(strings.NewReader("6\t22\t09101298"), '\t')
+	_, err := r.Read()
+	if p, ok := err.(*ParseError); ok {
+		if e, ok := p.Err.(*FileError); ok {
+			if e.FieldName != "Columns" {
+				t.Errorf("%T: %s", e, e)
+			}
+		} else {
+			t.Errorf("%T: %s", p.Err, p.Err)
+		}
+	} else {
+		t.Errorf("%T: %s", err, err)
+	}
+}
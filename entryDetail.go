@@ -7,6 +7,7 @@ package ach
 import (
 	"fmt"
 	"strconv"
+	"strings"
 )
 
 // EntryDetail contains the actual transaction data for an individual entry.
@@ -77,12 +78,28 @@ type EntryDetail struct {
 	// keeping separarte lists for different types of addenda...
 	Addendum       []Addenda
 	ReturnAddendum []ReturnAddenda
+	// Addenda98 is the Notification of Change addenda of a COR entry
+	Addenda98 *Addenda98
+	// Category is CategoryForward for an entry sent to the receiver or CategoryReturn for an
+	// entry returned to the originator. It is not written to the file. The Reader sets
+	// CategoryReturn on entries read with return addenda.
+	Category string
+	// Raw is the record as it was read when the Reader has the PreserveRawRecords option
+	Raw *RawRecord
 	// validator is composed for data validation
 	validator
 	// converters is composed for ACH to golang Converters
 	converters
 }
 
+const (
+	// CategoryForward is an entry sent from the originator to the receiver. An empty Category
+	// is also a forward entry.
+	CategoryForward = "Forward"
+	// CategoryReturn is an entry returned by the RDFI to the originator with return addenda.
+	CategoryReturn = "Return"
+)
+
 // EntryParam is the minimal fields required to make a ach entry
 type EntryParam struct {
 	ReceivingDFI      string `json:"receiving_dfi"`
@@ -120,7 +137,9 @@ func NewEntryDetail(params ...EntryParam) *EntryDetail {
 }
 
 // Parse takes the input record string and parses the EntryDetail values
+// A record shorter than RecordLength is padded with spaces.
 func (ed *EntryDetail) Parse(record string) {
+	record = ed.padRecord(record)
 	// 1-1 Always "6"
 	ed.recordType = "6"
 	// 2-3 is checking credit 22 debit 27 savings credit 32 debit 37
@@ -262,6 +281,21 @@ func (ed *EntryDetail) setTraceNumber(RDFIIdentification int, seq int) {
 	ed.TraceNumber = ed.parseNumField(trace)
 }
 
+// renumberTrace sets the trace number of the entry and updates the trace number of its addenda,
+// return addenda and Addenda98 to match.
+func (ed *EntryDetail) renumberTrace(RDFIIdentification int, seq int) {
+	ed.setTraceNumber(RDFIIdentification, seq)
+	for i := range ed.Addendum {
+		ed.Addendum[i].EntryDetailSequenceNumber = ed.parseNumField(ed.TraceNumberField()[8:])
+	}
+	for i := range ed.ReturnAddendum {
+		ed.ReturnAddendum[i].Trace = ed.TraceNumber
+	}
+	if ed.Addenda98 != nil {
+		ed.Addenda98.Trace = ed.TraceNumber
+	}
+}
+
 // RDFIIdentificationField get the rdfiIdentification with zero padding
 func (ed *EntryDetail) RDFIIdentificationField() string {
 	return ed.numericField(ed.RDFIIdentification, 8)
@@ -277,6 +311,11 @@ func (ed *EntryDetail) AmountField() string {
 	return ed.numericField(ed.Amount, 10)
 }
 
+// FormattedAmount returns Amount as a US dollar string. For example "$1,234.56"
+func (ed *EntryDetail) FormattedAmount() string {
+	return ed.formatDollars(ed.Amount)
+}
+
 // IdentificationNumberField returns a space padded string of IdentificationNumber
 func (ed *EntryDetail) IdentificationNumberField() string {
 	return ed.alphaField(ed.IdentificationNumber, 15)
@@ -292,6 +331,71 @@ func (ed *EntryDetail) ReceivingCompanyField() string {
 	return ed.IndividualNameField()
 }
 
+// CATXAddendaRecords returns the number of addenda records of a CTX entry. CTX entries carry
+// the count in the first 4 positions of the IndividualName field.
+func (ed *EntryDetail) CATXAddendaRecords() int {
+	return ed.parseNumField(ed.CATXAddendaRecordsField())
+}
+
+// CATXAddendaRecordsField returns the zero padded number of addenda records of a CTX entry
+func (ed *EntryDetail) CATXAddendaRecordsField() string {
+	return ed.IndividualNameField()[:4]
+}
+
+// SetCATXAddendaRecords sets the number of addenda records of a CTX entry in the first 4
+// positions of the IndividualName field.
+func (ed *EntryDetail) SetCATXAddendaRecords(i int) {
+	ed.IndividualName = ed.numericField(i, 4) + ed.IndividualNameField()[4:]
+}
+
+// CATXReceivingCompanyField returns the space padded receiving company name of a CTX entry.
+// CTX entries carry the name in positions 5-20 of the IndividualName field.
+func (ed *EntryDetail) CATXReceivingCompanyField() string {
+	return ed.IndividualNameField()[4:20]
+}
+
+// SetCATXReceivingCompany sets the receiving company name of a CTX entry in positions 5-20
+// of the IndividualName field.
+func (ed *EntryDetail) SetCATXReceivingCompany(s string) {
+	ed.IndividualName = ed.IndividualNameField()[:4] + ed.alphaField(s, 16) + ed.IndividualNameField()[20:]
+}
+
+// POPCheckSerialNumberField returns the space padded check serial number of a POP entry. POP
+// entries carry the serial number in positions 1-9 of the IdentificationNumber field.
+func (ed *EntryDetail) POPCheckSerialNumberField() string {
+	return ed.IdentificationNumberField()[:9]
+}
+
+// SetPOPCheckSerialNumber sets the check serial number of a POP entry in positions 1-9 of the
+// IdentificationNumber field.
+func (ed *EntryDetail) SetPOPCheckSerialNumber(s string) {
+	ed.IdentificationNumber = ed.alphaField(s, 9) + ed.IdentificationNumberField()[9:]
+}
+
+// POPTerminalCityField returns the abbreviated city of the terminal where a POP check was
+// converted. POP entries carry the city in positions 10-13 of the IdentificationNumber field.
+func (ed *EntryDetail) POPTerminalCityField() string {
+	return ed.IdentificationNumberField()[9:13]
+}
+
+// SetPOPTerminalCity sets the terminal city of a POP entry in positions 10-13 of the
+// IdentificationNumber field.
+func (ed *EntryDetail) SetPOPTerminalCity(s string) {
+	ed.IdentificationNumber = ed.IdentificationNumberField()[:9] + ed.alphaField(s, 4) + ed.IdentificationNumberField()[13:]
+}
+
+// POPTerminalStateField returns the state abbreviation of the terminal where a POP check was
+// converted. POP entries carry the state in positions 14-15 of the IdentificationNumber field.
+func (ed *EntryDetail) POPTerminalStateField() string {
+	return ed.IdentificationNumberField()[13:15]
+}
+
+// SetPOPTerminalState sets the terminal state of a POP entry in positions 14-15 of the
+// IdentificationNumber field.
+func (ed *EntryDetail) SetPOPTerminalState(s string) {
+	ed.IdentificationNumber = ed.IdentificationNumberField()[:13] + ed.alphaField(s, 2)
+}
+
 // DiscretionaryDataField returns a space padded string of DiscretionaryData
 func (ed *EntryDetail) DiscretionaryDataField() string {
 	return ed.alphaField(ed.DiscretionaryData, 2)
@@ -310,7 +414,129 @@ func (ed *EntryDetail) TraceNumberField() string {
 	return ed.numericField(ed.TraceNumber, 15)
 }
 
+// isZeroAmountAllowed returns true if the TransactionCode permits an Amount of zero. Prenotes,
+// zero dollar remittance entries and automated returns or notifications of change may be zero.
+func (ed *EntryDetail) isZeroAmountAllowed() bool {
+	switch ed.TransactionCode {
+	case 21, 23, 24, 26, 28, 29, 31, 33, 34, 36, 38, 39:
+		return true
+	}
+	return false
+}
+
+// isPrenote returns true if the TransactionCode is a prenotification rather than a live entry
+func (ed *EntryDetail) isPrenote() bool {
+	switch ed.TransactionCode {
+	case 23, 28, 33, 38:
+		return true
+	}
+	return false
+}
+
+// HasAddenda returns true if the AddendaRecordIndicator is set, indicating that one or
+// more addenda records follow the entry. AddAddenda and AddReturnAddenda set the indicator.
+func (ed *EntryDetail) HasAddenda() bool {
+	return ed.AddendaRecordIndicator == 1
+}
+
 // HasReturnAddenda returns true if entry has return addenda
 func (ed *EntryDetail) HasReturnAddenda() bool {
 	return ed.ReturnAddendum != nil
 }
+
+// AddDishonoredReturn adds a dishonored return addenda to the entry. code must be one of the
+// dishonored return codes R61, R62 or R67 through R70. originalTrace and originalDFI identify
+// the original entry and dishonored identifies the return entry being dishonored.
+func (ed *EntryDetail) AddDishonoredReturn(code string, originalTrace int, originalDFI string, dishonored DishonoredReturn) error {
+	if !isDishonoredReturnCode(code) {
+		msg := fmt.Sprintf(msgReturnAddendaDishonored, code)
+		return &FieldError{FieldName: "ReturnCode", Value: code, Msg: msg}
+	}
+	returnAddenda := NewReturnAddenda()
+	returnAddenda.ReturnCode = code
+	returnAddenda.OriginalTrace = originalTrace
+	returnAddenda.OriginalDFI = originalDFI
+	returnAddenda.Dishonored = &dishonored
+	return ed.addReturnAddenda(returnAddenda)
+}
+
+// AddContestedReturn adds a contested dishonored return addenda to the entry. code must be one
+// of the contested dishonored return codes R71 through R77. originalTrace and originalDFI identify
+// the original entry and contested identifies the return and dishonored return entries.
+func (ed *EntryDetail) AddContestedReturn(code string, originalTrace int, originalDFI string, contested ContestedReturn) error {
+	if !isContestedReturnCode(code) {
+		msg := fmt.Sprintf(msgReturnAddendaContested, code)
+		return &FieldError{FieldName: "ReturnCode", Value: code, Msg: msg}
+	}
+	returnAddenda := NewReturnAddenda()
+	returnAddenda.ReturnCode = code
+	returnAddenda.OriginalTrace = originalTrace
+	returnAddenda.OriginalDFI = originalDFI
+	returnAddenda.Contested = &contested
+	return ed.addReturnAddenda(returnAddenda)
+}
+
+// addReturnAddenda validates and appends returnAddenda to the entry
+func (ed *EntryDetail) addReturnAddenda(returnAddenda ReturnAddenda) error {
+	if ed.Addendum != nil {
+		return &FieldError{FieldName: "Addendum", Value: returnAddenda.ReturnCode, Msg: msgReturnAddendaAddendum}
+	}
+	if err := returnAddenda.Validate(); err != nil {
+		return err
+	}
+	ed.AddReturnAddenda(returnAddenda)
+	ed.Category = CategoryReturn
+	return nil
+}
+
+// ApplyNOC returns a copy of the entry with the correction from a Notification of Change
+// applied so it can be resubmitted. The original entry is not modified. An error is
+// returned for change codes that can not be applied to an entry detail record.
+func (ed *EntryDetail) ApplyNOC(a *Addenda98) (*EntryDetail, error) {
+	corrected := *ed
+	corrected.Addendum = append([]Addenda(nil), ed.Addendum...)
+	corrected.ReturnAddendum = nil
+	if len(corrected.Addendum) == 0 {
+		corrected.AddendaRecordIndicator = 0
+	}
+
+	if strings.TrimSpace(a.CorrectedData) == "" {
+		msg := fmt.Sprintf(msgAddenda98CorrectedData, a.ChangeCode)
+		return nil, &FieldError{FieldName: "CorrectedData", Value: a.CorrectedData, Msg: msg}
+	}
+	data := a.CorrectedDataField()
+	switch a.ChangeCode {
+	// Incorrect DFI Account Number
+	case "C01":
+		corrected.DFIAccountNumber = strings.TrimSpace(data[:17])
+	// Incorrect Routing Number
+	case "C02":
+		corrected.SetRDFI(corrected.parseNumField(data[:9]))
+	// Incorrect Routing Number and Incorrect DFI Account Number
+	case "C03":
+		corrected.SetRDFI(corrected.parseNumField(data[:9]))
+		corrected.DFIAccountNumber = strings.TrimSpace(data[12:29])
+	// Incorrect Individual Name / Receiving Company Name
+	case "C04":
+		corrected.IndividualName = strings.TrimSpace(data[:22])
+	// Incorrect Transaction Code
+	case "C05":
+		corrected.TransactionCode = corrected.parseNumField(data[:2])
+	// Incorrect DFI Account Number and Incorrect Transaction Code
+	case "C06":
+		corrected.DFIAccountNumber = strings.TrimSpace(data[:17])
+		corrected.TransactionCode = corrected.parseNumField(data[21:23])
+	// Incorrect Routing Number, Incorrect DFI Account Number, and Incorrect Transaction Code
+	case "C07":
+		corrected.SetRDFI(corrected.parseNumField(data[:9]))
+		corrected.DFIAccountNumber = strings.TrimSpace(data[9:26])
+		corrected.TransactionCode = corrected.parseNumField(data[26:28])
+	// Incorrect Individual Identification Number / Identification Number
+	case "C09":
+		corrected.IdentificationNumber = strings.TrimSpace(data[:22])
+	default:
+		msg := fmt.Sprintf(msgAddenda98ChangeCode, a.ChangeCode)
+		return nil, &FieldError{FieldName: "ChangeCode", Value: a.ChangeCode, Msg: msg}
+	}
+	return &corrected, nil
+}
@@ -287,3 +287,116 @@ func TestEDFieldInclusionTraceNumber(t *testing.T) {
 		}
 	}
 }
+
+func TestEDApplyNOC(t *testing.T) {
+	entry := mockEntryDetail()
+	a := mockAddenda98()
+	corrected, err := entry.ApplyNOC(a)
+	if err != nil {
+		t.Fatalf("%T: %s", err, err)
+	}
+	if corrected.DFIAccountNumber != "1918171614" {
+		t.Errorf("DFIAccountNumber Expected '1918171614' got: %v", corrected.DFIAccountNumber)
+	}
+	if entry.DFIAccountNumber != "123456789" {
+		t.Error("ApplyNOC modified the original entry")
+	}
+
+	// routing, account and transaction code
+	a.ChangeCode = "C07"
+	a.CorrectedData = "231380104" + "744-5678-99      " + "32"
+	corrected, err = entry.ApplyNOC(a)
+	if err != nil {
+		t.Fatalf("%T: %s", err, err)
+	}
+	if corrected.RDFIIdentificationField() != "23138010" || corrected.CheckDigit != 4 {
+		t.Errorf("RDFIIdentification Expected '23138010' got: %v", corrected.RDFIIdentificationField())
+	}
+	if corrected.DFIAccountNumber != "744-5678-99" {
+		t.Errorf("DFIAccountNumber Expected '744-5678-99' got: %v", corrected.DFIAccountNumber)
+	}
+	if corrected.TransactionCode != 32 {
+		t.Errorf("TransactionCode Expected 32 got: %v", corrected.TransactionCode)
+	}
+	if err := corrected.Validate(); err != nil {
+		t.Errorf("%T: %s", err, err)
+	}
+}
+
+func TestEDApplyNOCChangeCode(t *testing.T) {
+	entry := mockEntryDetail()
+	a := mockAddenda98()
+	a.ChangeCode = "C13"
+	if _, err := entry.ApplyNOC(a); err != nil {
+		if e, ok := err.(*FieldError); ok {
+			if e.FieldName != "ChangeCode" {
+				t.Errorf("%T: %s", err, err)
+			}
+		}
+	} else {
+		t.Error("expected error for change code C13")
+	}
+}
+
+func TestEDHasAddenda(t *testing.T) {
+	entry := mockEntryDetail()
+	if entry.HasAddenda() {
+		t.Error("HasAddenda Expected false for entry without addenda")
+	}
+	entry.AddAddenda(mockAddenda())
+	if !entry.HasAddenda() {
+		t.Error("HasAddenda Expected true after AddAddenda")
+	}
+}
+
+func TestEDValidateForSEC(t *testing.T) {
+	tests := []struct {
+		sec       string
+		code      int
+		fieldName string
+	}{
+		{"PPD", 22, ""},
+		{"PPD", 24, "TransactionCode"},
+		{"WEB", 27, ""},
+		{"CCD", 24, ""},
+		{"CTX", 39, ""},
+		{"TRX", 27, ""},
+		{"TRX", 22, "TransactionCode"},
+		{"COR", 21, ""},
+		{"COR", 22, "TransactionCode"},
+		{"ENR", 22, "Amount"},
+		{"RCK", 22, ""},
+	}
+	for _, test := range tests {
+		entry := mockEntryDetail()
+		entry.TransactionCode = test.code
+		err := entry.ValidateForSEC(test.sec)
+		if test.fieldName == "" {
+			if err != nil {
+				t.Errorf("%s %d: %s", test.sec, test.code, err)
+			}
+			continue
+		}
+		if e, ok := err.(*FieldError); ok {
+			if e.FieldName != test.fieldName {
+				t.Errorf("%s %d: %s", test.sec, test.code, err)
+			}
+		} else {
+			t.Errorf("%s %d expected FieldError got: %v", test.sec, test.code, err)
+		}
+	}
+
+	entry := mockEntryDetail()
+	entry.DiscretionaryData = "X"
+	if err := entry.ValidateForSEC("WEB"); err == nil {
+		t.Error("expected PaymentType error")
+	}
+	entry.AddAddenda(mockAddenda())
+	if err := entry.ValidateForSEC("CTX"); err == nil {
+		t.Error("expected CATXAddendaRecords error")
+	}
+	entry.SetCATXAddendaRecords(1)
+	if err := entry.ValidateForSEC("CTX"); err != nil {
+		t.Errorf("%T: %s", err, err)
+	}
+}
@@ -0,0 +1,131 @@
+// Copyright 2017 The ACH Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package ach
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+var (
+	msgEntrySECTransactionCode = "%v is not allowed for SEC code %v"
+	msgBatchWebPaymentType     = "%v is not a valid payment type S (single entry) or R (recurring)"
+	msgBatchCTXAddendaRecords  = "%v entry detail addenda records not equal to addendum %v for trace number %v"
+	msgBatchENRAmount          = "%v must be zero for ENR entries"
+	msgEntrySECMaxAmount       = "%v exceeds the %v limit for SEC code %v"
+	msgEntryPOPCheckSerial     = "check serial number is required for POP entries"
+	msgEntryPOPTerminal        = "%q is not a terminal city and state for POP entries"
+)
+
+// entrySECRule holds the entry detail field requirements of a SEC code.
+type entrySECRule struct {
+	// transactionCodes are the TransactionCodes allowed for the SEC code. nil allows any valid code.
+	transactionCodes []int
+	// zeroAmount requires an Amount of zero
+	zeroAmount bool
+	// paymentType requires a PaymentType of S (single entry) or R (recurring)
+	paymentType bool
+	// catxAddendaRecords requires the number of addenda records in the entry to equal its addenda
+	catxAddendaRecords bool
+	// maxAmount is the largest Amount allowed. Zero is no limit.
+	maxAmount int
+	// popFields requires the check serial number and terminal city and state of a POP entry
+	popFields bool
+}
+
+var (
+	// consumerTransactionCodes are the checking and savings codes for consumer accounts
+	consumerTransactionCodes = []int{21, 22, 23, 26, 27, 28, 31, 32, 33, 36, 37, 38}
+	// corporateTransactionCodes add the zero dollar remittance codes allowed for CCD and CTX
+	corporateTransactionCodes = append([]int{24, 29, 34, 39}, consumerTransactionCodes...)
+)
+
+// entrySECRules are the entry detail field requirements of each SEC code. SEC codes without
+// a rule have no requirements beyond EntryDetail.Validate.
+var entrySECRules = map[string]entrySECRule{
+	ppd: {transactionCodes: consumerTransactionCodes},
+	web: {transactionCodes: consumerTransactionCodes, paymentType: true},
+	ccd: {transactionCodes: corporateTransactionCodes},
+	ctx: {transactionCodes: corporateTransactionCodes, catxAddendaRecords: true},
+	// TRX entries only debit the account of the paying bank
+	trx: {transactionCodes: []int{26, 27, 28, 36, 37, 38}, catxAddendaRecords: true},
+	// COR entries are automated notifications of change
+	cor: {transactionCodes: []int{21, 26, 31, 36}},
+	enr: {zeroAmount: true},
+	// POP entries debit a consumer account for a check converted at the point of purchase
+	pop: {transactionCodes: []int{26, 27, 36, 37}, maxAmount: 2500000, popFields: true},
+}
+
+// ValidateForSEC checks the entry against the field requirements of the SEC code sec. These
+// are the TransactionCodes allowed for the SEC code, the zero Amount of ENR entries, the
+// PaymentType of WEB entries, the addenda records count of CTX and TRX entries and the
+// Amount limit, check serial number and terminal of POP entries. Batches call ValidateForSEC
+// with the SEC code of their header when they are validated.
+func (ed *EntryDetail) ValidateForSEC(sec string) error {
+	rule, ok := entrySECRules[sec]
+	if !ok {
+		return nil
+	}
+	if rule.transactionCodes != nil && !containsInt(rule.transactionCodes, ed.TransactionCode) {
+		msg := fmt.Sprintf(msgEntrySECTransactionCode, ed.TransactionCode, sec)
+		return &FieldError{FieldName: "TransactionCode", Value: strconv.Itoa(ed.TransactionCode), Msg: msg}
+	}
+	if rule.zeroAmount && ed.Amount != 0 {
+		msg := fmt.Sprintf(msgBatchENRAmount, ed.Amount)
+		return &FieldError{FieldName: "Amount", Value: ed.AmountField(), Msg: msg}
+	}
+	if rule.paymentType {
+		// "R" For a recurring WEB Entry
+		// "S" For a Single-Entry WEB Entry
+		if !strings.Contains(strings.ToUpper(ed.PaymentType()), "S") && !strings.Contains(strings.ToUpper(ed.PaymentType()), "R") {
+			msg := fmt.Sprintf(msgBatchWebPaymentType, ed.PaymentType())
+			return &FieldError{FieldName: "PaymentType", Value: ed.PaymentType(), Msg: msg}
+		}
+	}
+	if rule.catxAddendaRecords && ed.CATXAddendaRecords() != len(ed.Addendum) {
+		msg := fmt.Sprintf(msgBatchCTXAddendaRecords, ed.CATXAddendaRecords(), len(ed.Addendum), ed.TraceNumberField())
+		return &FieldError{FieldName: "CATXAddendaRecords", Value: ed.CATXAddendaRecordsField(), Msg: msg}
+	}
+	if rule.maxAmount > 0 && ed.Amount > rule.maxAmount {
+		msg := fmt.Sprintf(msgEntrySECMaxAmount, ed.FormattedAmount(), ed.formatDollars(rule.maxAmount), sec)
+		return &FieldError{FieldName: "Amount", Value: ed.AmountField(), Msg: msg}
+	}
+	if rule.popFields {
+		if strings.TrimSpace(ed.POPCheckSerialNumberField()) == "" {
+			return &FieldError{FieldName: "POPCheckSerialNumber", Value: ed.POPCheckSerialNumberField(), Msg: msgEntryPOPCheckSerial}
+		}
+		// the city is abbreviated to 4 characters and the state is a 2 letter postal code
+		city, state := ed.POPTerminalCityField(), ed.POPTerminalStateField()
+		if strings.TrimSpace(city) == "" || !isAlphaUpper(state) {
+			msg := fmt.Sprintf(msgEntryPOPTerminal, city+state)
+			return &FieldError{FieldName: "POPTerminal", Value: city + state, Msg: msg}
+		}
+	}
+	return nil
+}
+
+// isAlphaUpper returns true if s is not empty and only contains the letters A-Z
+func isAlphaUpper(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, c := range s {
+		if c < 'A' || c > 'Z' {
+			return false
+		}
+	}
+	return true
+}
+
+// containsInt returns true if v is in values
+func containsInt(values []int, v int) bool {
+	for _, value := range values {
+		if value == v {
+			return true
+		}
+	}
+	return false
+}
@@ -3,6 +3,7 @@ package main
 import (
 	"fmt"
 	"os"
+	"time"
 
 	"github.com/moov-io/ach"
 )
@@ -25,6 +26,7 @@ func main() {
 		CompanyIdentification:   "123456789",
 		CompanyEntryDescription: "Trans. Description",
 		CompanyDescriptiveDate:  "Oct 23",
+		EffectiveEntryDate:      time.Now().AddDate(0, 0, 1).Format("060102"),
 		ODFIIdentification:      "123456789"})
 
 	// To create an entry
@@ -65,6 +67,7 @@ func main() {
 		CompanyIdentification:   "123456789",
 		CompanyEntryDescription: "subscr",
 		CompanyDescriptiveDate:  "Oct 23",
+		EffectiveEntryDate:      time.Now().AddDate(0, 0, 1).Format("060102"),
 		ODFIIdentification:      "123456789"})
 
 	// Add an entry and define if it is a single or reoccuring payment
@@ -9,8 +9,13 @@
 package ach
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
+	"sort"
 	"strconv"
+	"strings"
+	"time"
 )
 
 // First position of all Record Types. These codes are uniquely assigned to
@@ -25,6 +30,10 @@ const (
 
 	// RecordLength character count of each line representing a letter in a file
 	RecordLength = 94
+	// LineLimit is the largest number of lines, including block padding, accepted in a file
+	LineLimit = 10000
+	// BlockingFactor is the number of records in each block of a file
+	BlockingFactor = 10
 )
 
 // currently supported SEC codes
@@ -33,6 +42,10 @@ const (
 	web = "WEB"
 	ccd = "CCD"
 	cor = "COR"
+	ctx = "CTX"
+	enr = "ENR"
+	trx = "TRX"
+	pop = "POP"
 )
 
 // Errors strings specific to parsing a Batch container
@@ -40,13 +53,32 @@ var (
 	msgFileControlEquality           = "header %v is not equal to control %v"
 	msgFileCalculatedControlEquality = "calculated %v is out-of-balance with control %v"
 	// specific messages
-	msgRecordLength      = "must be 94 characters and found %d"
-	msgFileBatchOutside  = "outside of current batch"
-	msgFileBatchInside   = "inside of current batch"
-	msgFileControl       = "none or more than one file control exists"
-	msgFileHeader        = "none or more than one file headers exists"
-	msgUnknownRecordType = "%s is an unknown record type"
-	msgFileNoneSEC       = "%v SEC(standard entry class) is not implemented"
+	msgRecordLength             = "must be 94 characters and found %d"
+	msgReaderRecordLength       = "must be %d characters and found %d"
+	msgReaderBlockPadding       = "%d records including padding is not a multiple of blocking factor %d"
+	msgReaderBlockCount         = "does not match %d blocks of %d records"
+	msgFileBatchOutside         = "outside of current batch"
+	msgFileBatchInside          = "inside of current batch"
+	msgFileControl              = "none or more than one file control exists"
+	msgFileHeader               = "none or more than one file headers exists"
+	msgUnknownRecordType        = "%s is an unknown record type"
+	msgFileNoneSEC              = "%v SEC(standard entry class) is not implemented"
+	msgFileReturnTrace          = "is zero for return entry trace number %v"
+	msgFileUniformODFI          = "%v does not match first batch ODFI %v"
+	msgFileDuplicateBatchHeader = "is identical to the header of batch %v"
+	msgFileDuplicateBatchNumber = "is used by more than one batch"
+	msgFileSplitEntryLines      = "entry and addenda need %d lines which exceeds %d lines per file"
+	msgFileMaxAddenda           = "%d addenda records exceeds the limit of %d per file"
+	msgFileMixedADV             = "%v %v can not be in the same file as batch %v with %v %v"
+	msgFileReturnCodeEntryType  = "%v can only return %v entries and found transaction code %v"
+	msgFileIDModifiers          = "%d files exceeds the %d file ID modifiers"
+	msgFileIDModifiersUsed      = "all %d file ID modifiers are used"
+	msgFileBatchIndex           = "is out of range for %d batches"
+	msgFileBatchNil             = "can not replace a batch with nil"
+	msgFileOriginODFI           = "%v does not match ImmediateOrigin ODFI %v"
+	msgFilePaymentDelimiter     = "contains delimiter %q in addenda of entry %v"
+	msgFileSplitBatchEntries    = "%d entry and addenda records exceeds %d per file"
+	msgFilePrenoteReturnAddenda = "forward prenote %v has return addenda and is not in the return category"
 )
 
 // FileError is an error describing issues validating a file
@@ -81,6 +113,57 @@ type FileParam struct {
 	ReferenceCode            string `json:"reference_code,omitempty"`
 }
 
+// SplitOpts contains the limits used by File.SplitBy. A zero limit is not applied.
+type SplitOpts struct {
+	// MaxEntriesPerFile limits the number of entry and addenda records in each file.
+	MaxEntriesPerFile int `json:"max_entries_per_file,omitempty"`
+	// MaxBatchesPerFile limits the number of batches in each file.
+	MaxBatchesPerFile int `json:"max_batches_per_file,omitempty"`
+}
+
+// ValidateOpts contains optional checks that can be performed by File.ValidateWith in
+// addition to the NACHA rules checked by Validate. All checks default to off.
+type ValidateOpts struct {
+	// RequireReturnOriginalTrace requires every return entry to carry a non-zero
+	// OriginalTrace in its return addenda so it can be correlated to the original entry.
+	RequireReturnOriginalTrace bool `json:"require_return_original_trace,omitempty"`
+	// RequireUniformODFI requires every batch in the file to have the same ODFIIdentification.
+	RequireUniformODFI bool `json:"require_uniform_odfi,omitempty"`
+	// WarnOnFutureCreationDate reports a FileCreationDate after the current date.
+	WarnOnFutureCreationDate bool `json:"warn_on_future_creation_date,omitempty"`
+	// WarnOnDuplicateBatchHeaders reports batches with identical headers other than the
+	// batch number. These batches should usually have been combined into one batch.
+	WarnOnDuplicateBatchHeaders bool `json:"warn_on_duplicate_batch_headers,omitempty"`
+	// RequireUniqueBatchNumbers requires the BatchNumber of every batch in the file to be unique.
+	RequireUniqueBatchNumbers bool `json:"require_unique_batch_numbers,omitempty"`
+	// RequireSegregatedPrenotes validates every batch as SetRequireSegregatedPrenotes does so
+	// prenote and live entries are in separate batches.
+	RequireSegregatedPrenotes bool `json:"require_segregated_prenotes,omitempty"`
+	// RequireImmediateNames requires a non-blank ImmediateDestinationName and ImmediateOriginName
+	// in the file header.
+	RequireImmediateNames bool `json:"require_immediate_names,omitempty"`
+	// MaxAddendaPerFile limits the total number of addenda records in the file. Zero is no limit.
+	MaxAddendaPerFile int `json:"max_addenda_per_file,omitempty"`
+	// RequireReturnCodeEntryType requires the ReturnCode of each return entry to be valid for
+	// returning the original entry type. For example R01 can only be used to return a debit.
+	RequireReturnCodeEntryType bool `json:"require_return_code_entry_type,omitempty"`
+	// RejectPaymentDelimiters rejects Addenda05 PaymentRelatedInformation containing the "*" or "\"
+	// delimiters of structured remittance formats. CTX, ENR and TRX batches use the delimiters and
+	// are not checked.
+	RejectPaymentDelimiters bool `json:"reject_payment_delimiters,omitempty"`
+	// RejectForwardPrenoteReturnAddenda rejects prenote entries with return addenda unless the
+	// entry Category is CategoryReturn. Only a returned prenote carries an Addenda99.
+	RejectForwardPrenoteReturnAddenda bool `json:"reject_forward_prenote_return_addenda,omitempty"`
+	// RejectTruncatedCompanyName rejects a batch header CompanyName longer than its 16 character
+	// field instead of truncating it when the file is written.
+	RejectTruncatedCompanyName bool `json:"reject_truncated_company_name,omitempty"`
+	// RequireSameDaySettlementDate requires a julian SettlementDate in each batch header that settles
+	// same day and a blank SettlementDate in every other batch header. A batch settles same day when
+	// its CompanyDescriptiveDate begins with "SD" or its EffectiveEntryDate is not after the
+	// FileCreationDate.
+	RequireSameDaySettlementDate bool `json:"require_same_day_settlement_date,omitempty"`
+}
+
 // NewFile constructs a file template.
 func NewFile(params ...FileParam) *File {
 	if len(params) > 0 {
@@ -99,6 +182,12 @@ func NewFile(params ...FileParam) *File {
 
 // Create creates a valid file and requires that the FileHeader and at least one Batch
 func (f *File) Create() error {
+	return f.create(false)
+}
+
+// create builds the File Control from the batches. Batches are numbered in ascending
+// sequence from 1 unless preserveBatchNumbers is set.
+func (f *File) create(preserveBatchNumbers bool) error {
 	// Requires a valid FileHeader to build FileControl
 	if err := f.Header.Validate(); err != nil {
 		return err
@@ -116,8 +205,12 @@ func (f *File) Create() error {
 	totalCreditAmount := 0
 	for i, batch := range f.Batches {
 		// create ascending batch numbers
-		f.Batches[i].GetHeader().BatchNumber = batchSeq
-		f.Batches[i].GetControl().BatchNumber = batchSeq
+		if preserveBatchNumbers {
+			f.Batches[i].GetControl().BatchNumber = f.Batches[i].GetHeader().BatchNumber
+		} else {
+			f.Batches[i].GetHeader().BatchNumber = batchSeq
+			f.Batches[i].GetControl().BatchNumber = batchSeq
+		}
 		batchSeq++
 		// sum file entry and addenda records. Assume batch.Create() batch properly calculated control
 		fileEntryAddendaCount = fileEntryAddendaCount + batch.GetControl().EntryAddendaCount
@@ -132,12 +225,7 @@ func (f *File) Create() error {
 	// create FileControl from calculated values
 	fc := NewFileControl()
 	fc.BatchCount = batchSeq - 1
-	// blocking factor of 10 is static default value in f.Header.blockingFactor.
-	if (totalRecordsInFile % 10) != 0 {
-		fc.BlockCount = totalRecordsInFile/10 + 1
-	} else {
-		fc.BlockCount = totalRecordsInFile / 10
-	}
+	fc.BlockCount = blockCount(totalRecordsInFile)
 	fc.EntryAddendaCount = fileEntryAddendaCount
 	fc.EntryHash = fileEntryHashSum
 	fc.TotalDebitEntryDollarAmountInFile = totalDebitAmount
@@ -147,30 +235,721 @@ func (f *File) Create() error {
 	return nil
 }
 
+// FileOpts controls optional behavior of CreateWith.
+type FileOpts struct {
+	// GlobalTraceSequencing assigns trace numbers from one ascending sequence across every
+	// batch in the file instead of the sequence that restarts at 1 in each batch.
+	GlobalTraceSequencing bool `json:"global_trace_sequencing,omitempty"`
+	// PreserveBatchNumbers keeps the BatchNumber of each batch header, such as the numbers of a
+	// file that was read, instead of numbering the batches from 1.
+	PreserveBatchNumbers bool `json:"preserve_batch_numbers,omitempty"`
+}
+
+// CreateWith creates a valid file as Create does after applying opts. With GlobalTraceSequencing
+// every entry is given the ODFIIdentification of its batch and the next sequence number in the
+// file, replacing trace numbers assigned by the batches. Addenda are updated to the new trace numbers.
+func (f *File) CreateWith(opts FileOpts) error {
+	if opts.GlobalTraceSequencing {
+		seq := 1
+		for _, batch := range f.Batches {
+			odfi := batch.GetHeader().ODFIIdentification
+			for _, entry := range batch.GetEntries() {
+				entry.renumberTrace(odfi, seq)
+				seq++
+			}
+		}
+	}
+	return f.create(opts.PreserveBatchNumbers)
+}
+
+// CalculateBlockCount returns the number of blocks needed for the records currently in the
+// file, including the File Header and File Control. It is calculated from the entries and
+// addenda in each batch and can be compared against Control.BlockCount without calling Create.
+func (f *File) CalculateBlockCount() int {
+	return blockCount(f.recordCount())
+}
+
+// recordCount returns the number of records in the file before the final block is padded
+func (f *File) recordCount() int {
+	// add 2 for FileHeader/control
+	totalRecordsInFile := 2
+	for _, batch := range f.Batches {
+		// add 2 for Batch header/control
+		totalRecordsInFile += 2
+		for _, entry := range batch.GetEntries() {
+			totalRecordsInFile += 1 + len(entry.Addendum) + len(entry.ReturnAddendum)
+			if entry.Addenda98 != nil {
+				totalRecordsInFile++
+			}
+		}
+		if adv, ok := batch.(*BatchADV); ok {
+			totalRecordsInFile += len(adv.GetADVEntries())
+		}
+	}
+	return totalRecordsInFile
+}
+
+// LineCount returns the number of lines the file is written as with blocks of blockingFactor
+// records, including the File Header, File Control and the padding of the final block. Pass the
+// n given to WriterBlockingFactor, or BlockingFactor for the default Writer. A blockingFactor
+// less than 1 is BlockingFactor. It is calculated from the records currently in the file like
+// CalculateBlockCount.
+func (f *File) LineCount(blockingFactor int) int {
+	if blockingFactor < 1 {
+		blockingFactor = BlockingFactor
+	}
+	return blockCountFactor(f.recordCount(), blockingFactor) * blockingFactor
+}
+
+// ExceedsLineLimit returns true if the file is written as more than LineLimit lines and would be
+// rejected. SplitByLineCount can split the file into files within the limit.
+func (f *File) ExceedsLineLimit() bool {
+	return f.LineCount(BlockingFactor) > LineLimit
+}
+
+// IsADV returns true if the file contains automated accounting advice batches. ADV files are
+// written with ADV File Control records.
+func (f *File) IsADV() bool {
+	if len(f.Batches) == 0 {
+		return false
+	}
+	_, ok := f.Batches[0].(*BatchADV)
+	return ok
+}
+
+// DominantSEC returns the StandardEntryClassCode of the batches with the most entries in the file.
+// Entries of batches with the same code are counted together and a tie is broken by the code that
+// sorts first. An empty string is returned for a file without entries.
+func (f *File) DominantSEC() string {
+	counts := make(map[string]int)
+	for _, batch := range f.Batches {
+		sec := batch.GetHeader().StandardEntryClassCode
+		if adv, ok := batch.(*BatchADV); ok {
+			counts[sec] += len(adv.GetADVEntries())
+			continue
+		}
+		counts[sec] += len(batch.GetEntries())
+	}
+	dominant := ""
+	for sec, count := range counts {
+		if count == 0 {
+			continue
+		}
+		if dominant == "" || count > counts[dominant] || (count == counts[dominant] && sec < dominant) {
+			dominant = sec
+		}
+	}
+	return dominant
+}
+
+// AddendaCount returns the total number of addenda, return addenda and Addenda98 records
+// attached to the entries of every batch in the file. ADV entries are entry records without
+// addenda and are not counted.
+func (f *File) AddendaCount() int {
+	count := 0
+	for _, batch := range f.Batches {
+		entries := len(batch.GetEntries())
+		if adv, ok := batch.(*BatchADV); ok {
+			entries = len(adv.GetADVEntries())
+		}
+		count += batch.EntryAddendaCount() - entries
+	}
+	return count
+}
+
+// StripAddenda removes the addenda, return addenda and Addenda98 records from every entry in the
+// file for receivers that reject addenda and returns the number of records removed. The
+// AddendaRecordIndicator of each entry is set to 0 and CTX and TRX entries are set to zero addenda
+// records. Batches and the file must be created again to recompute their controls.
+func (f *File) StripAddenda() int {
+	count := 0
+	for _, batch := range f.Batches {
+		sec := batch.GetHeader().StandardEntryClassCode
+		for _, entry := range batch.GetEntries() {
+			count += len(entry.Addendum) + len(entry.ReturnAddendum)
+			if entry.Addenda98 != nil {
+				count++
+			}
+			entry.Addendum = nil
+			entry.ReturnAddendum = nil
+			entry.Addenda98 = nil
+			entry.AddendaRecordIndicator = 0
+			if sec == ctx || sec == trx {
+				entry.SetCATXAddendaRecords(0)
+			}
+		}
+	}
+	return count
+}
+
+// HasAddenda returns true if any entry in any batch of the file has addenda or return addenda records.
+func (f *File) HasAddenda() bool {
+	for _, batch := range f.Batches {
+		for _, entry := range batch.GetEntries() {
+			if entry.HasAddenda() {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// EffectiveEntryDates returns the distinct EffectiveEntryDate of the batches in the file sorted
+// from earliest to latest. Batches with a blank or invalid EffectiveEntryDate are skipped.
+func (f *File) EffectiveEntryDates() []time.Time {
+	var dates []time.Time
+	seen := make(map[string]bool)
+	for _, batch := range f.Batches {
+		date := batch.GetHeader().EffectiveEntryDate
+		if date.IsZero() || seen[f.formatSimpleDate(date)] {
+			continue
+		}
+		seen[f.formatSimpleDate(date)] = true
+		dates = append(dates, date)
+	}
+	sort.Slice(dates, func(i, j int) bool { return dates[i].Before(dates[j]) })
+	return dates
+}
+
+// FormattedTotalDebit returns the file control total debit amount as a US dollar string.
+// For example "$1,234.56"
+func (f *File) FormattedTotalDebit() string {
+	return f.formatDollars(f.Control.TotalDebitEntryDollarAmountInFile)
+}
+
+// FormattedTotalCredit returns the file control total credit amount as a US dollar string.
+// For example "$1,234.56"
+func (f *File) FormattedTotalCredit() string {
+	return f.formatDollars(f.Control.TotalCreditEntryDollarAmountInFile)
+}
+
+// SuspiciousEntries returns the entries of every batch whose Amount is negative, greater than
+// maxAmount or zero for a TransactionCode that does not permit a zero amount. These amounts
+// frequently come from misaligned fields in a corrupt file.
+func (f *File) SuspiciousEntries(maxAmount int) []*EntryDetail {
+	var entries []*EntryDetail
+	for _, batch := range f.Batches {
+		for _, entry := range batch.GetEntries() {
+			if entry.Amount < 0 || entry.Amount > maxAmount || (entry.Amount == 0 && !entry.isZeroAmountAllowed()) {
+				entries = append(entries, entry)
+			}
+		}
+	}
+	return entries
+}
+
+// RedactForLog returns a single line summary of the file for logging. It contains record counts,
+// dollar totals, SEC codes and the sorted routing numbers of each batch, each listed once with the
+// number of entries to it such as rdfi=231380104:2. Account numbers, names, identification numbers and
+// addenda information are omitted.
+func (f *File) RedactForLog() string {
+	fields := []string{
+		"destination=" + f.Header.ImmediateDestinationField()[1:],
+		"origin=" + f.Header.ImmediateOriginField()[1:],
+		"created=" + f.Header.FileCreationDateField(),
+		"batches=" + strconv.Itoa(len(f.Batches)),
+		"debit=" + strconv.Itoa(f.Control.TotalDebitEntryDollarAmountInFile),
+		"credit=" + strconv.Itoa(f.Control.TotalCreditEntryDollarAmountInFile),
+	}
+	for _, batch := range f.Batches {
+		counts := make(map[string]int)
+		for _, entry := range batch.GetEntries() {
+			counts[entry.RDFIIdentificationField()+strconv.Itoa(entry.CheckDigit)]++
+		}
+		var rdfis []string
+		for routing, count := range counts {
+			rdfis = append(rdfis, fmt.Sprintf("%s:%d", routing, count))
+		}
+		sort.Strings(rdfis)
+		fields = append(fields, fmt.Sprintf("batch=%d sec=%s odfi=%s entries=%d debit=%d credit=%d rdfi=%s",
+			batch.GetHeader().BatchNumber,
+			batch.GetHeader().StandardEntryClassCode,
+			batch.GetHeader().ODFIIdentificationField(),
+			len(batch.GetEntries()),
+			batch.GetControl().TotalDebitEntryDollarAmount,
+			batch.GetControl().TotalCreditEntryDollarAmount,
+			strings.Join(rdfis, ",")))
+	}
+	return strings.Join(fields, " ")
+}
+
+// IdempotencyKey returns a hex encoded SHA-256 hash of the ImmediateDestination and
+// ImmediateOrigin of the File Header and the batch header, entry and addenda records of each
+// batch. The FileCreationDate, FileCreationTime and FileIDModifier and the control records are
+// left out, so a file that is created or resubmitted again has the same key and the key can be
+// used to find a file that was already submitted.
+func (f *File) IdempotencyKey() string {
+	hash := sha256.New()
+	// writes to a hash do not return errors
+	fmt.Fprintln(hash, f.Header.ImmediateDestinationField(), f.Header.ImmediateOriginField())
+	for _, batch := range f.Batches {
+		fmt.Fprintln(hash, batch.GetHeader().String())
+		if adv, ok := batch.(*BatchADV); ok {
+			for _, entry := range adv.GetADVEntries() {
+				fmt.Fprintln(hash, entry.String())
+			}
+			continue
+		}
+		for _, entry := range batch.GetEntries() {
+			fmt.Fprintln(hash, entry.String())
+			for _, addenda := range entry.Addendum {
+				fmt.Fprintln(hash, addenda.String())
+			}
+			for _, returnAddenda := range entry.ReturnAddendum {
+				fmt.Fprintln(hash, returnAddenda.String())
+			}
+			if entry.Addenda98 != nil {
+				fmt.Fprintln(hash, entry.Addenda98.String())
+			}
+		}
+	}
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// EntryHashByRouting returns the entry hash of the entries to each RDFI keyed by the 9 digit
+// routing number. The hash is the sum of the 8 digit RDFI identifications truncated to the
+// rightmost 10 digits as in the batch and file control records.
+func (f *File) EntryHashByRouting() map[string]int {
+	hashes := make(map[string]int)
+	for _, batch := range f.Batches {
+		for _, entry := range batch.GetEntries() {
+			routing := entry.RDFIIdentificationField() + strconv.Itoa(entry.CheckDigit)
+			hashes[routing] = f.parseNumField(f.numericField(hashes[routing]+entry.RDFIIdentification, 10))
+		}
+	}
+	return hashes
+}
+
+// EntryColumns returns the column names of the rows returned by File.EntryRows.
+func EntryColumns() []string {
+	return []string{
+		"file_origin",
+		"batch_sec",
+		"trace_number",
+		"routing_number",
+		"account_number",
+		"amount",
+		"name",
+		"category",
+		"return_code",
+	}
+}
+
+// EntryRows returns every entry in the file flattened to a row of the columns in EntryColumns.
+// The category is "NOC" for entries in COR batches, "Return" for entries with return addenda
+// and "Forward" for all other entries. The return code is blank for entries that are not returns.
+func (f *File) EntryRows() [][]string {
+	var rows [][]string
+	for _, batch := range f.Batches {
+		sec := batch.GetHeader().StandardEntryClassCode
+		for _, entry := range batch.GetEntries() {
+			category, returnCode := "Forward", ""
+			switch {
+			case sec == cor:
+				category = "NOC"
+			case len(entry.ReturnAddendum) > 0:
+				category = "Return"
+				returnCode = entry.ReturnAddendum[0].ReturnCode
+			}
+			rows = append(rows, []string{
+				f.Header.ImmediateOriginField()[1:],
+				sec,
+				entry.TraceNumberField(),
+				entry.RDFIIdentificationField() + strconv.Itoa(entry.CheckDigit),
+				strings.TrimSpace(entry.DFIAccountNumber),
+				strconv.Itoa(entry.Amount),
+				strings.TrimSpace(entry.IndividualName),
+				category,
+				returnCode,
+			})
+		}
+	}
+	return rows
+}
+
+// SplitByLineCount splits the file into files of at most maxLines lines each, counting the
+// padding of the final block as LineCount(BlockingFactor) does. An entry is never separated from its addenda
+// and a batch that does not fit in one file is continued in a batch with the same header and
+// options in the next file. The entries are copied so f is not changed. The batches and controls
+// of each file are created. An error is returned if an entry and its addenda do not fit in a
+// file with one batch.
+func (f *File) SplitByLineCount(maxLines int) ([]*File, error) {
+	// records are written in full blocks so only whole blocks of records fit in maxLines
+	limit := maxLines - maxLines%BlockingFactor
+	var files []*File
+	var file *File
+	lines := 0
+	for _, batch := range f.Batches {
+		src, err := cloneBatch(batch)
+		if err != nil {
+			return nil, err
+		}
+		var split Batcher
+		adv, isADV := src.(*BatchADV)
+		count := len(src.GetEntries())
+		if isADV {
+			count = len(adv.GetADVEntries())
+		}
+		for i := 0; i < count; i++ {
+			// ADV entries do not have addenda
+			entryLines := 1
+			fieldName, value := "SequenceNumber", ""
+			if isADV {
+				value = adv.GetADVEntries()[i].SequenceNumberField()
+			} else {
+				entry := src.GetEntries()[i]
+				entryLines += len(entry.Addendum) + len(entry.ReturnAddendum)
+				if entry.Addenda98 != nil {
+					entryLines++
+				}
+				fieldName, value = "TraceNumber", entry.TraceNumberField()
+			}
+			// file header, batch header, batch control and file control records
+			if needed := blockCount(entryLines+4) * BlockingFactor; needed > maxLines {
+				msg := fmt.Sprintf(msgFileSplitEntryLines, needed, maxLines)
+				return nil, &FileError{FieldName: fieldName, Value: value, Msg: msg}
+			}
+			if split == nil {
+				entryLines += 2
+			}
+			if file == nil || lines+entryLines > limit {
+				file = NewFile().SetHeader(f.Header)
+				files = append(files, file)
+				lines = 2
+				if split != nil {
+					split = nil
+					entryLines += 2
+				}
+			}
+			if split == nil {
+				b, err := newBatchFrom(src)
+				if err != nil {
+					return nil, err
+				}
+				file.AddBatch(b)
+				split = b
+			}
+			if isADV {
+				split.(*BatchADV).AddADVEntry(adv.GetADVEntries()[i])
+			} else {
+				split.AddEntry(src.GetEntries()[i])
+			}
+			lines += entryLines
+		}
+	}
+	for _, file := range files {
+		for _, batch := range file.Batches {
+			if err := batch.Create(); err != nil {
+				return nil, err
+			}
+		}
+		if err := file.Create(); err != nil {
+			return nil, err
+		}
+	}
+	return files, nil
+}
+
+// SplitBy splits the file into files within the limits of opts. Batches are copied to the new
+// files in order and are never split, so trace numbers are unchanged. Each file is created with
+// batch numbers starting at 1 and when there is more than one file they are given FileIDModifiers
+// A, B, C and so on. A BatchError is returned if a batch exceeds MaxEntriesPerFile on its own.
+func (f *File) SplitBy(opts SplitOpts) ([]*File, error) {
+	var files []*File
+	var file *File
+	entries := 0
+	for _, batch := range f.Batches {
+		count := batch.EntryAddendaCount()
+		if opts.MaxEntriesPerFile > 0 && count > opts.MaxEntriesPerFile {
+			msg := fmt.Sprintf(msgFileSplitBatchEntries, count, opts.MaxEntriesPerFile)
+			return nil, &BatchError{BatchNumber: batch.GetHeader().BatchNumber, FieldName: "EntryAddendaCount", Msg: msg}
+		}
+		if file == nil ||
+			(opts.MaxEntriesPerFile > 0 && entries+count > opts.MaxEntriesPerFile) ||
+			(opts.MaxBatchesPerFile > 0 && len(file.Batches) >= opts.MaxBatchesPerFile) {
+			file = NewFile().SetHeader(f.Header)
+			files = append(files, file)
+			entries = 0
+		}
+		clone, err := cloneBatch(batch)
+		if err != nil {
+			return nil, err
+		}
+		file.AddBatch(clone)
+		entries += count
+	}
+	for _, file := range files {
+		if err := file.Create(); err != nil {
+			return nil, err
+		}
+	}
+	if len(files) > 1 {
+		if err := AssignFileIDModifiers(files); err != nil {
+			return nil, err
+		}
+	}
+	return files, nil
+}
+
+// ResolveTraceCollisions renumbers each batch with an entry that has the same trace number as an
+// earlier entry in the file and returns the number of colliding entries. The entries of a
+// renumbered batch are given ascending trace numbers with the ODFIIdentification of the batch,
+// starting above the highest sequence number used with that ODFIIdentification in the file, and
+// their addenda are updated to match. Create must be called after trace numbers are changed.
+func (f *File) ResolveTraceCollisions() int {
+	// highest sequence number used with each ODFIIdentification
+	highest := make(map[int]int)
+	for _, batch := range f.Batches {
+		for _, entry := range batch.GetEntries() {
+			odfi, seq := entry.TraceNumber/10000000, entry.TraceNumber%10000000
+			if seq > highest[odfi] {
+				highest[odfi] = seq
+			}
+		}
+	}
+	seen := make(map[int]bool)
+	resolved := 0
+	for _, batch := range f.Batches {
+		collisions := 0
+		batchSeen := make(map[int]bool)
+		for _, entry := range batch.GetEntries() {
+			if seen[entry.TraceNumber] || batchSeen[entry.TraceNumber] {
+				collisions++
+			}
+			batchSeen[entry.TraceNumber] = true
+		}
+		if collisions > 0 {
+			odfi := batch.GetHeader().ODFIIdentification
+			for _, entry := range batch.GetEntries() {
+				highest[odfi]++
+				entry.renumberTrace(odfi, highest[odfi])
+			}
+			resolved += collisions
+		}
+		for _, entry := range batch.GetEntries() {
+			seen[entry.TraceNumber] = true
+		}
+	}
+	return resolved
+}
+
+// IsControlStale recalculates the control totals of the batches and file with Totals and returns
+// true if any differ from the stored controls. A stale file must be created before it is written.
+// A file with a batch that can not be created is also stale. Batch numbers and reserved fields are
+// not compared so files created with PreserveBatchNumbers or read with PreserveReserved are kept.
+func (f *File) IsControlStale() bool {
+	totals, err := f.Totals()
+	if err != nil {
+		return true
+	}
+	records := 2
+	for i, batch := range f.Batches {
+		bc, bt := batch.GetControl(), totals.Batches[i]
+		if bc.EntryAddendaCount != bt.EntryAddendaCount ||
+			bc.EntryHashField() != bc.numericField(bt.EntryHash, 10) ||
+			bc.TotalDebitEntryDollarAmount != bt.TotalDebit ||
+			bc.TotalCreditEntryDollarAmount != bt.TotalCredit {
+			return true
+		}
+		records += 2 + bt.EntryAddendaCount
+	}
+	fc := f.Control
+	return fc.BatchCount != len(f.Batches) ||
+		fc.BlockCount != blockCount(records) ||
+		fc.EntryAddendaCount != totals.EntryAddendaCount ||
+		fc.EntryHashField() != fc.numericField(totals.EntryHash, 10) ||
+		fc.TotalDebitEntryDollarAmountInFile != totals.TotalDebit ||
+		fc.TotalCreditEntryDollarAmountInFile != totals.TotalCredit
+}
+
+// DryRunCreate runs Create on a copy of each batch and returns the errors in the order of
+// f.Batches. The error is nil for a batch that would be created. The file is not changed.
+func (f *File) DryRunCreate() []error {
+	errs := make([]error, len(f.Batches))
+	for i, batch := range f.Batches {
+		clone, err := cloneBatch(batch)
+		if err != nil {
+			errs[i] = err
+			continue
+		}
+		errs[i] = clone.Create()
+	}
+	return errs
+}
+
+// blockCount returns the number of blocks for a record count rounded up to a full block.
+// blocking factor of 10 is static default value in f.Header.blockingFactor.
+func blockCount(records int) int {
+	return blockCountFactor(records, BlockingFactor)
+}
+
+// blockCountFactor returns the number of blocks of factor records for a record count rounded
+// up to a full block.
+func blockCountFactor(records, factor int) int {
+	if (records % factor) != 0 {
+		return records/factor + 1
+	}
+	return records / factor
+}
+
 // AddBatch appends a Batch to the ach.File
 func (f *File) AddBatch(batch Batcher) []Batcher {
 	f.Batches = append(f.Batches, batch)
 	return f.Batches
 }
 
+// RemoveBatch removes the batch at index from the file. The File Control is reset so the next
+// call to Create recomputes it from the remaining batches. Removing the only batch leaves a
+// File Control with zero totals that validates against the empty file.
+func (f *File) RemoveBatch(index int) error {
+	if err := f.isBatchIndex(index); err != nil {
+		return err
+	}
+	f.Batches = append(f.Batches[:index], f.Batches[index+1:]...)
+	f.resetControl()
+	return nil
+}
+
+// ReplaceBatch replaces the batch at index with batch. The File Control is reset so the next
+// call to Create recomputes it.
+func (f *File) ReplaceBatch(index int, batch Batcher) error {
+	if err := f.isBatchIndex(index); err != nil {
+		return err
+	}
+	if batch == nil {
+		return &FileError{FieldName: "Batches", Value: strconv.Itoa(index), Msg: msgFileBatchNil}
+	}
+	f.Batches[index] = batch
+	f.resetControl()
+	return nil
+}
+
+// isBatchIndex returns an error if index is not the index of a batch in the file
+func (f *File) isBatchIndex(index int) error {
+	if index < 0 || index >= len(f.Batches) {
+		msg := fmt.Sprintf(msgFileBatchIndex, len(f.Batches))
+		return &FileError{FieldName: "Batches", Value: strconv.Itoa(index), Msg: msg}
+	}
+	return nil
+}
+
+// batchError returns a BatchError for the field of the batch at index i of the file
+func (f *File) batchError(i int, fieldName, msg string) *BatchError {
+	return &BatchError{BatchNumber: f.Batches[i].GetHeader().BatchNumber, FieldName: fieldName, Msg: msg, batchIndex: i + 1}
+}
+
+// entryError returns a BatchError for the field of the entry at index j of the batch at index i
+// of the file
+func (f *File) entryError(i, j int, fieldName, msg string) *BatchError {
+	e := f.batchError(i, fieldName, msg)
+	e.entryIndex = j + 1
+	return e
+}
+
+// locateBatchError sets the index i of the batch in the file on err if it is a BatchError
+func locateBatchError(i int, err error) error {
+	if e, ok := err.(*BatchError); ok {
+		e.batchIndex = i + 1
+	}
+	return err
+}
+
+// resetControl replaces the File Control with a new File Control that must be rebuilt by Create.
+// A file without batches gets the block count of its File Header and File Control.
+func (f *File) resetControl() {
+	f.Control = NewFileControl()
+	if len(f.Batches) == 0 {
+		f.Control.BlockCount = blockCount(2)
+	}
+}
+
+// BatchCount returns the number of batches in the file. It can be compared against a
+// maximum batch count policy before accepting a file without calling Create or Validate.
+func (f *File) BatchCount() int {
+	return len(f.Batches)
+}
+
+// EachBatch calls fn for each batch in the file in order. Iteration stops and the error is
+// returned when fn returns an error.
+func (f *File) EachBatch(fn func(Batcher) error) error {
+	for _, batch := range f.Batches {
+		if err := fn(batch); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// BatchByNumber returns the batch with a header BatchNumber of n and true if it was found.
+func (f *File) BatchByNumber(n int) (Batcher, bool) {
+	for _, batch := range f.Batches {
+		if batch.GetHeader().BatchNumber == n {
+			return batch, true
+		}
+	}
+	return nil, false
+}
+
 // SetHeader allows for header to be built.
 func (f *File) SetHeader(h FileHeader) *File {
 	f.Header = h
 	return f
 }
 
+// fileIDModifiers are the FileIDModifier values in the order they are assigned
+const fileIDModifiers = "ABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
+
+// AssignFileIDModifiers sets the FileIDModifier of each file in sequence A through Z and then
+// 0 through 9 so files sent to the same destination on the same day can be told apart. An error
+// is returned without changing the files if there are more files than FileIDModifier values.
+func AssignFileIDModifiers(files []*File) error {
+	if len(files) > len(fileIDModifiers) {
+		msg := fmt.Sprintf(msgFileIDModifiers, len(files), len(fileIDModifiers))
+		return &FileError{FieldName: "FileIDModifier", Value: strconv.Itoa(len(files)), Msg: msg}
+	}
+	for i, f := range files {
+		f.Header.FileIDModifier = fileIDModifiers[i : i+1]
+	}
+	return nil
+}
+
+// NextFileIDModifier sets the FileIDModifier of the file to the first value in sequence A
+// through Z and then 0 through 9 that is not in used and returns it. used are the modifiers of
+// files already sent to the same destination on the same day. An error is returned without
+// changing the file if every FileIDModifier value is used.
+func (f *File) NextFileIDModifier(used []string) (string, error) {
+	taken := make(map[string]bool, len(used))
+	for _, modifier := range used {
+		taken[strings.ToUpper(strings.TrimSpace(modifier))] = true
+	}
+	for i := range fileIDModifiers {
+		if modifier := fileIDModifiers[i : i+1]; !taken[modifier] {
+			f.Header.FileIDModifier = modifier
+			return modifier, nil
+		}
+	}
+	msg := fmt.Sprintf(msgFileIDModifiersUsed, len(fileIDModifiers))
+	return "", &FileError{FieldName: "FileIDModifier", Value: strconv.Itoa(len(used)), Msg: msg}
+}
+
 // Validate NACHA rules on the entire batch before being added to a File
 func (f *File) Validate() error {
-	// The value of the Batch Count Field is equal to the number of Company/Batch/Header Records in the file.
-	if f.Control.BatchCount != len(f.Batches) {
-		msg := fmt.Sprintf(msgFileCalculatedControlEquality, len(f.Batches), f.Control.BatchCount)
-		return &FileError{FieldName: "BatchCount", Value: strconv.Itoa(len(f.Batches)), Msg: msg}
+	if err := f.isBatchCount(); err != nil {
+		return err
+	}
+
+	if err := f.isEffectiveEntryDates(); err != nil {
+		return err
 	}
 
 	if err := f.isEntryAddendaCount(); err != nil {
 		return err
 	}
 
+	if err := f.isADVSegregated(); err != nil {
+		return err
+	}
+
 	if err := f.isFileAmount(); err != nil {
 		return err
 	}
@@ -182,6 +961,297 @@ func (f *File) Validate() error {
 	return nil
 }
 
+// ValidateWith performs the NACHA rule checks of Validate and then the optional checks
+// enabled in opts. A nil opts is the same as calling Validate.
+func (f *File) ValidateWith(opts *ValidateOpts) error {
+	if err := f.Validate(); err != nil {
+		return err
+	}
+	if opts == nil {
+		return nil
+	}
+	if opts.RequireReturnOriginalTrace {
+		if err := f.isReturnOriginalTrace(); err != nil {
+			return err
+		}
+	}
+	if opts.RequireUniformODFI {
+		if err := f.isUniformODFI(); err != nil {
+			return err
+		}
+	}
+	if opts.WarnOnFutureCreationDate {
+		if err := f.Header.isFileCreationDateFuture(time.Now()); err != nil {
+			return err
+		}
+	}
+	if opts.WarnOnDuplicateBatchHeaders {
+		if err := f.isDuplicateBatchHeader(); err != nil {
+			return err
+		}
+	}
+	if opts.RequireUniqueBatchNumbers {
+		if err := f.isUniqueBatchNumber(); err != nil {
+			return err
+		}
+	}
+	if opts.RequireSegregatedPrenotes {
+		if err := f.validateBatchesWith(func(o *batchOptions) { o.requireSegregatedPrenotes = true }); err != nil {
+			return err
+		}
+	}
+	if opts.RequireImmediateNames {
+		if err := f.Header.isImmediateNames(); err != nil {
+			return err
+		}
+	}
+	if opts.RequireReturnCodeEntryType {
+		if err := f.isReturnCodeEntryType(); err != nil {
+			return err
+		}
+	}
+	if opts.RejectPaymentDelimiters {
+		if err := f.isPaymentDelimiters(); err != nil {
+			return err
+		}
+	}
+	if opts.RejectForwardPrenoteReturnAddenda {
+		if err := f.isForwardPrenoteReturnAddenda(); err != nil {
+			return err
+		}
+	}
+	if opts.RejectTruncatedCompanyName {
+		if err := f.isCompanyNameLength(); err != nil {
+			return err
+		}
+	}
+	if opts.RequireSameDaySettlementDate {
+		if err := f.isSameDaySettlementDate(); err != nil {
+			return err
+		}
+	}
+	if opts.MaxAddendaPerFile > 0 {
+		if count := f.AddendaCount(); count > opts.MaxAddendaPerFile {
+			msg := fmt.Sprintf(msgFileMaxAddenda, count, opts.MaxAddendaPerFile)
+			return &FileError{FieldName: "AddendaCount", Value: strconv.Itoa(count), Msg: msg}
+		}
+	}
+	return nil
+}
+
+// validateBatchesWith validates a copy of every batch with the batch options set by fn.
+func (f *File) validateBatchesWith(fn func(*batchOptions)) error {
+	for i, batch := range f.Batches {
+		clone, err := cloneBatch(batch)
+		if err != nil {
+			return err
+		}
+		if b, ok := clone.(batchOptioner); ok {
+			fn(b.options())
+		}
+		if err := clone.Validate(); err != nil {
+			return locateBatchError(i, err)
+		}
+	}
+	return nil
+}
+
+// isCompanyNameLength checks that the CompanyName of every batch header fits its field.
+func (f *File) isCompanyNameLength() error {
+	for i, batch := range f.Batches {
+		if err := batch.GetHeader().isCompanyNameLength(); err != nil {
+			return f.batchError(i, "CompanyName", err.(*FieldError).Msg)
+		}
+	}
+	return nil
+}
+
+// isADVSegregated checks that automated accounting advice batches (SEC code ADV or service class
+// code 280) are not in the same file as other batches.
+func (f *File) isADVSegregated() error {
+	if len(f.Batches) == 0 {
+		return nil
+	}
+	first := f.Batches[0].GetHeader()
+	for i, batch := range f.Batches {
+		bh := batch.GetHeader()
+		if first.isADV() != bh.isADV() {
+			msg := fmt.Sprintf(msgFileMixedADV, bh.ServiceClassCode, bh.StandardEntryClassCode, first.BatchNumber,
+				first.ServiceClassCode, first.StandardEntryClassCode)
+			return f.batchError(i, "ServiceClassCode", msg)
+		}
+	}
+	return nil
+}
+
+// isUniqueBatchNumber checks that no two batches have the same BatchNumber.
+func (f *File) isUniqueBatchNumber() error {
+	batchNumbers := make(map[int]bool)
+	for i, batch := range f.Batches {
+		batchNumber := batch.GetHeader().BatchNumber
+		if batchNumbers[batchNumber] {
+			return f.batchError(i, "BatchNumber", msgFileDuplicateBatchNumber)
+		}
+		batchNumbers[batchNumber] = true
+	}
+	return nil
+}
+
+// isDuplicateBatchHeader checks that no two batches have the same header fields. The batch
+// number in positions 88-94 is not compared.
+func (f *File) isDuplicateBatchHeader() error {
+	headers := make(map[string]int)
+	for i, batch := range f.Batches {
+		header := batch.GetHeader().String()[:87]
+		if batchNumber, ok := headers[header]; ok {
+			msg := fmt.Sprintf(msgFileDuplicateBatchHeader, batchNumber)
+			return f.batchError(i, "BatchHeader", msg)
+		}
+		headers[header] = batch.GetHeader().BatchNumber
+	}
+	return nil
+}
+
+// isUniformODFI checks that every batch has the same ODFIIdentification as the first batch.
+func (f *File) isUniformODFI() error {
+	if len(f.Batches) == 0 {
+		return nil
+	}
+	odfi := f.Batches[0].GetHeader().ODFIIdentificationField()
+	for i, batch := range f.Batches {
+		if batch.GetHeader().ODFIIdentificationField() != odfi {
+			msg := fmt.Sprintf(msgFileUniformODFI, batch.GetHeader().ODFIIdentificationField(), odfi)
+			return f.batchError(i, "ODFIIdentification", msg)
+		}
+	}
+	return nil
+}
+
+// OriginODFIMismatches checks that the ODFIIdentification of every batch and the trace number
+// prefix of every entry match the ODFI of the File Header ImmediateOrigin, as they do when the
+// file is originated by a single ODFI. A BatchError is returned for each batch header and entry
+// that does not match. The check is not part of Validate.
+func (f *File) OriginODFIMismatches() []error {
+	var errs []error
+	odfi := f.Header.ImmediateOriginField()[1:9]
+	for i, batch := range f.Batches {
+		bh := batch.GetHeader()
+		if bh.ODFIIdentificationField() != odfi {
+			msg := fmt.Sprintf(msgFileOriginODFI, bh.ODFIIdentificationField(), odfi)
+			errs = append(errs, f.batchError(i, "ODFIIdentification", msg))
+		}
+		for j, entry := range batch.GetEntries() {
+			if prefix := entry.TraceNumberField()[:8]; prefix != odfi {
+				msg := fmt.Sprintf(msgFileOriginODFI, entry.TraceNumberField(), odfi)
+				errs = append(errs, f.entryError(i, j, "TraceNumber", msg))
+			}
+		}
+	}
+	return errs
+}
+
+// isReturnOriginalTrace checks that each return addenda references the trace number of
+// the original entry. A zero OriginalTrace can not be correlated to an entry.
+func (f *File) isReturnOriginalTrace() error {
+	for i, batch := range f.Batches {
+		for j, entry := range batch.GetEntries() {
+			for _, returnAddenda := range entry.ReturnAddendum {
+				if returnAddenda.OriginalTrace == 0 {
+					msg := fmt.Sprintf(msgFileReturnTrace, entry.TraceNumberField())
+					return f.entryError(i, j, "OriginalTrace", msg)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// isReturnCodeEntryType checks the ReturnCode of each return entry is valid for the original entry
+// type. A return is a credit when the last digit of its transaction code is 1 through 4 and a debit
+// when it is 5 through 9. For example 21 returns a checking credit and 26 returns a checking debit.
+func (f *File) isReturnCodeEntryType() error {
+	for i, batch := range f.Batches {
+		for j, entry := range batch.GetEntries() {
+			entryType := "credit"
+			if entry.TransactionCode%10 >= 5 {
+				entryType = "debit"
+			}
+			for _, returnAddenda := range entry.ReturnAddendum {
+				allowed, ok := returnCodeEntryTypes[returnAddenda.ReturnCode]
+				if ok && allowed != entryType {
+					msg := fmt.Sprintf(msgFileReturnCodeEntryType, returnAddenda.ReturnCode, allowed, entry.TransactionCode)
+					return f.entryError(i, j, "ReturnCode", msg)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// isForwardPrenoteReturnAddenda checks that only prenote entries in the return category have
+// return addenda.
+func (f *File) isForwardPrenoteReturnAddenda() error {
+	for i, batch := range f.Batches {
+		for j, entry := range batch.GetEntries() {
+			if entry.isPrenote() && entry.HasReturnAddenda() && entry.Category != CategoryReturn {
+				msg := fmt.Sprintf(msgFilePrenoteReturnAddenda, entry.TraceNumberField())
+				return f.entryError(i, j, "Category", msg)
+			}
+		}
+	}
+	return nil
+}
+
+// paymentDelimiters are the characters with special meaning in structured remittance formats
+const paymentDelimiters = "*\\"
+
+// isPaymentDelimiters checks that no Addenda05 PaymentRelatedInformation contains paymentDelimiters.
+// CTX, ENR and TRX addenda carry ANSI X12 or enrollment data that uses the delimiters so their
+// batches are not checked.
+func (f *File) isPaymentDelimiters() error {
+	for i, batch := range f.Batches {
+		switch batch.GetHeader().StandardEntryClassCode {
+		case ctx, enr, trx:
+			continue
+		}
+		for j, entry := range batch.GetEntries() {
+			for _, addenda := range entry.Addendum {
+				if addenda.TypeCode != "05" {
+					continue
+				}
+				if k := strings.IndexAny(addenda.PaymentRelatedInformation, paymentDelimiters); k >= 0 {
+					msg := fmt.Sprintf(msgFilePaymentDelimiter, addenda.PaymentRelatedInformation[k:k+1], entry.TraceNumberField())
+					return f.entryError(i, j, "PaymentRelatedInformation", msg)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// isBatchCount validates the Batch Count Field is equal to the number of Company/Batch/Header Records in the file.
+func (f *File) isBatchCount() error {
+	if f.Control.BatchCount != len(f.Batches) {
+		msg := fmt.Sprintf(msgFileCalculatedControlEquality, len(f.Batches), f.Control.BatchCount)
+		return &FileError{FieldName: "BatchCount", Value: strconv.Itoa(len(f.Batches)), Msg: msg}
+	}
+	return nil
+}
+
+// isEffectiveEntryDates checks that every forward batch has an EffectiveEntryDate. Return and
+// notification of change batches may leave it blank and ADV batches are not checked.
+func (f *File) isEffectiveEntryDates() error {
+	for i, batch := range f.Batches {
+		if _, ok := batch.(*BatchADV); ok {
+			continue
+		}
+		if _, err := batch.EffectiveEntryDate(); err != nil {
+			return locateBatchError(i, err)
+		}
+	}
+	return nil
+}
+
 // isEntryAddenda is prepared by hashing the RDFI’s 8-digit Routing Number in each entry.
 //The Entry Hash provides a check against inadvertent alteration of data
 func (f *File) isEntryAddendaCount() error {
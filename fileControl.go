@@ -30,7 +30,8 @@ type FileControl struct {
 
 	// TotalCreditEntryDollarAmountInFile contains accumulated Batch credit totals within the file.
 	TotalCreditEntryDollarAmountInFile int
-	// Reserved should be blank.
+	// Reserved should be blank. Positions 56-94 are only kept as read when the Reader is
+	// created with PreserveReserved and are written space padded.
 	reserved string
 	// validator is composed for data validation
 	validator
@@ -39,7 +40,9 @@ type FileControl struct {
 }
 
 // Parse takes the input record string and parses the FileControl values
+// A record shorter than RecordLength is padded with spaces.
 func (fc *FileControl) Parse(record string) {
+	record = fc.padRecord(record)
 	// 1-1 Always "9"
 	fc.recordType = "9"
 	// 2-7 The total number of Batch Header Record in the file. For example: "000003
@@ -77,7 +80,7 @@ func (fc *FileControl) String() string {
 		fc.EntryHashField(),
 		fc.TotalDebitEntryDollarAmountInFileField(),
 		fc.TotalCreditEntryDollarAmountInFileField(),
-		fc.reserved,
+		fc.alphaField(fc.reserved, 39),
 	)
 }
 
@@ -89,6 +89,35 @@ func TestFCString(t *testing.T) {
 	}
 }
 
+// TestFCReserved the reserved positions are written blank unless the Reader preserves them
+func TestFCReserved(t *testing.T) {
+	var line = "9000001000001000000010005320001000000010500000000000000RELAY 0001                             "
+	var blank = "9000001000001000000010005320001000000010500000000000000                                       "
+	r := NewReader(strings.NewReader(line))
+	r.line = line
+	if err := r.parseFileControl(); err != nil {
+		t.Errorf("%T: %s", err, err)
+	}
+	if r.File.Control.String() != blank {
+		t.Errorf("\nStrings do not match %s\n %s", blank, r.File.Control.String())
+	}
+
+	r = NewReader(strings.NewReader(line), PreserveReserved())
+	r.line = line
+	if err := r.parseFileControl(); err != nil {
+		t.Errorf("%T: %s", err, err)
+	}
+	if r.File.Control.String() != line {
+		t.Errorf("\nStrings do not match %s\n %s", line, r.File.Control.String())
+	}
+
+	// a File Control that was not created with NewFileControl is still a full record
+	fc := FileControl{recordType: "9", BatchCount: 1, BlockCount: 1, EntryAddendaCount: 1, EntryHash: 5320001, TotalCreditEntryDollarAmountInFile: 10500}
+	if fc.String() != "9000001000001000000010005320001000000000000000000010500                                       " {
+		t.Errorf("unexpected File Control %q", fc.String())
+	}
+}
+
 // TestValidateFCRecordType ensure error if recordType is not 9
 func TestValidateFCRecordType(t *testing.T) {
 	fc := mockFileControl()
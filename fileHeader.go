@@ -6,17 +6,20 @@ package ach
 
 import (
 	"fmt"
+	"strconv"
 	"strings"
 	"time"
 )
 
 // Errors specific to a File Header Record
 var (
-	msgRecordType       = "received expecting %d"
-	msgRecordSize       = "is not 094"
-	msgBlockingFactor   = "is not 10"
-	msgFormatCode       = "is not 1"
-	msgFileCreationDate = "was created before " + time.Now().String()
+	msgRecordType             = "received expecting %d"
+	msgRecordSize             = "is not 094"
+	msgBlockingFactor         = "is not 10"
+	msgFormatCode             = "is not 1"
+	msgFileCreationDate       = "was created before " + time.Now().String()
+	msgFileCreationDateFuture = "is after the current date %v"
+	msgFileIDSequence         = "%d is not a file ID modifier sequence of 0 through 35"
 )
 
 // FileHeader is a Record designating physical file characteristics and identify
@@ -114,7 +117,9 @@ func NewFileHeader(params ...FileParam) FileHeader {
 }
 
 // Parse takes the input record string and parses the FileHeader values
+// A record shorter than RecordLength is padded with spaces.
 func (fh *FileHeader) Parse(record string) {
+	record = fh.padRecord(record)
 	// (character position 1-1) Always "1"
 	fh.recordType = "1"
 	// (2-3) Always "01"
@@ -210,6 +215,42 @@ func (fh *FileHeader) Validate() error {
 	return nil
 }
 
+// isFileCreationDateFuture returns an error if the FileCreationDate is after the date of now.
+// A future creation date usually indicates a clock problem on the system creating the file.
+func (fh *FileHeader) isFileCreationDateFuture(now time.Time) error {
+	created := time.Date(fh.FileCreationDate.Year(), fh.FileCreationDate.Month(), fh.FileCreationDate.Day(), 0, 0, 0, 0, time.UTC)
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	if created.After(today) {
+		msg := fmt.Sprintf(msgFileCreationDateFuture, fh.formatSimpleDate(now))
+		return &FieldError{FieldName: "FileCreationDate", Value: fh.FileCreationDateField(), Msg: msg}
+	}
+	return nil
+}
+
+// isImmediateNames returns an error if ImmediateDestinationName or ImmediateOriginName is blank.
+// The names are optional in NACHA but some receiving points reject files without them.
+func (fh *FileHeader) isImmediateNames() error {
+	if strings.TrimSpace(fh.ImmediateDestinationName) == "" {
+		return &FieldError{FieldName: "ImmediateDestinationName", Value: fh.ImmediateDestinationName, Msg: msgFieldInclusion}
+	}
+	if strings.TrimSpace(fh.ImmediateOriginName) == "" {
+		return &FieldError{FieldName: "ImmediateOriginName", Value: fh.ImmediateOriginName, Msg: msgFieldInclusion}
+	}
+	return nil
+}
+
+// SetFileIDModifierFromSequence sets the FileIDModifier for the nth file of a day. Sequences 0
+// through 9 are the modifiers "0" through "9" and 10 through 35 are "A" through "Z". An error is
+// returned without changing the FileIDModifier for a sequence outside 0 through 35.
+func (fh *FileHeader) SetFileIDModifierFromSequence(n int) error {
+	if n < 0 || n > 35 {
+		msg := fmt.Sprintf(msgFileIDSequence, n)
+		return &FieldError{FieldName: "FileIDModifier", Value: strconv.Itoa(n), Msg: msg}
+	}
+	fh.FileIDModifier = strings.ToUpper(strconv.FormatInt(int64(n), 36))
+	return nil
+}
+
 // fieldInclusion validate mandatory fields are not default values. If fields are
 // invalid the ACH transfer will be returned.
 func (fh *FileHeader) fieldInclusion() error {
@@ -316,3 +316,32 @@ func TestFHFieldInclusionFormatCode(t *testing.T) {
 		}
 	}
 }
+
+func TestFHSetFileIDModifierFromSequence(t *testing.T) {
+	fh := mockFileHeader()
+	for n, want := range map[int]string{0: "0", 9: "9", 10: "A", 26: "Q", 35: "Z"} {
+		if err := fh.SetFileIDModifierFromSequence(n); err != nil {
+			t.Errorf("%T: %s", err, err)
+		}
+		if fh.FileIDModifier != want {
+			t.Errorf("sequence %d expected %q got: %q", n, want, fh.FileIDModifier)
+		}
+		if err := fh.Validate(); err != nil {
+			t.Errorf("%T: %s", err, err)
+		}
+	}
+	fh.FileIDModifier = "Z"
+	for _, n := range []int{-1, 36} {
+		err := fh.SetFileIDModifierFromSequence(n)
+		if e, ok := err.(*FieldError); ok {
+			if e.FieldName != "FileIDModifier" {
+				t.Errorf("%T: %s", err, err)
+			}
+		} else {
+			t.Errorf("expected FieldError got: %v", err)
+		}
+		if fh.FileIDModifier != "Z" {
+			t.Errorf("sequence %d changed FileIDModifier to %q", n, fh.FileIDModifier)
+		}
+	}
+}
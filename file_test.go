@@ -5,7 +5,12 @@
 package ach
 
 import (
+	"bytes"
+	"errors"
+	"io/ioutil"
+	"strings"
 	"testing"
+	"time"
 )
 
 func mockFilePPD() *File {
@@ -163,3 +168,1253 @@ func TestFileBuildNoBatch(t *testing.T) {
 		}
 	}
 }
+
+func TestFileCalculateBlockCount(t *testing.T) {
+	file := mockFilePPD()
+	if file.CalculateBlockCount() != file.Control.BlockCount {
+		t.Errorf("CalculateBlockCount Expected %v got: %v", file.Control.BlockCount, file.CalculateBlockCount())
+	}
+	// 5 records in the file, adding 6 entries requires a second block.
+	for i := 0; i < 6; i++ {
+		file.Batches[0].AddEntry(mockEntryDetail())
+	}
+	if file.CalculateBlockCount() != 2 {
+		t.Errorf("CalculateBlockCount Expected 2 got: %v", file.CalculateBlockCount())
+	}
+	if file.Control.BlockCount != 1 {
+		t.Error("CalculateBlockCount modified the file control")
+	}
+}
+
+func TestFileValidateWithReturnOriginalTrace(t *testing.T) {
+	file := mockFilePPD()
+	file.Batches[0].GetEntries()[0].AddReturnAddenda(ReturnAddenda{ReturnCode: "R01"})
+	if err := file.ValidateWith(nil); err != nil {
+		t.Errorf("%T: %s", err, err)
+	}
+	err := file.ValidateWith(&ValidateOpts{RequireReturnOriginalTrace: true})
+	if e, ok := err.(*BatchError); ok {
+		if e.FieldName != "OriginalTrace" {
+			t.Errorf("%T: %s", err, err)
+		}
+	} else {
+		t.Errorf("expected BatchError got: %v", err)
+	}
+
+	file.Batches[0].GetEntries()[0].ReturnAddendum[0].OriginalTrace = 99912340000015
+	if err := file.ValidateWith(&ValidateOpts{RequireReturnOriginalTrace: true}); err != nil {
+		t.Errorf("%T: %s", err, err)
+	}
+}
+
+func TestFileAddendaCount(t *testing.T) {
+	file := mockFilePPD()
+	if file.AddendaCount() != 0 {
+		t.Errorf("AddendaCount Expected 0 got: %v", file.AddendaCount())
+	}
+	file.Batches[0].GetEntries()[0].AddAddenda(mockAddenda())
+	batch := mockBatchPPD()
+	batch.GetEntries()[0].AddReturnAddenda(ReturnAddenda{})
+	file.AddBatch(batch)
+	if file.AddendaCount() != 2 {
+		t.Errorf("AddendaCount Expected 2 got: %v", file.AddendaCount())
+	}
+	file.AddBatch(mockBatchCOR())
+	if file.AddendaCount() != 3 {
+		t.Errorf("AddendaCount Expected 3 with Addenda98 got: %v", file.AddendaCount())
+	}
+}
+
+func TestFileValidateWithUniformODFI(t *testing.T) {
+	file := mockFilePPD()
+	batch := NewBatchPPD()
+	bh := mockBatchHeader()
+	bh.ODFIIdentification = 23138010
+	batch.SetHeader(bh)
+	batch.AddEntry(mockEntryDetail())
+	if err := batch.Create(); err != nil {
+		t.Fatalf("%T: %s", err, err)
+	}
+	file.AddBatch(batch)
+	if err := file.Create(); err != nil {
+		t.Fatalf("%T: %s", err, err)
+	}
+	if err := file.ValidateWith(&ValidateOpts{}); err != nil {
+		t.Errorf("%T: %s", err, err)
+	}
+	err := file.ValidateWith(&ValidateOpts{RequireUniformODFI: true})
+	if e, ok := err.(*BatchError); ok {
+		if e.FieldName != "ODFIIdentification" || e.BatchNumber != 2 {
+			t.Errorf("%T: %s", err, err)
+		}
+	} else {
+		t.Errorf("expected BatchError got: %v", err)
+	}
+}
+
+func TestFileValidateWithFutureCreationDate(t *testing.T) {
+	file := mockFilePPD()
+	opts := &ValidateOpts{WarnOnFutureCreationDate: true}
+	if err := file.ValidateWith(opts); err != nil {
+		t.Errorf("%T: %s", err, err)
+	}
+	file.Header.FileCreationDate = time.Now().AddDate(0, 0, 2)
+	if err := file.ValidateWith(nil); err != nil {
+		t.Errorf("%T: %s", err, err)
+	}
+	err := file.ValidateWith(opts)
+	if e, ok := err.(*FieldError); ok {
+		if e.FieldName != "FileCreationDate" {
+			t.Errorf("%T: %s", err, err)
+		}
+	} else {
+		t.Errorf("expected FieldError got: %v", err)
+	}
+}
+
+func TestFileBatchByNumber(t *testing.T) {
+	file := mockFilePPD()
+	file.AddBatch(mockBatchPPD())
+	if err := file.Create(); err != nil {
+		t.Fatalf("%T: %s", err, err)
+	}
+	batch, ok := file.BatchByNumber(2)
+	if !ok || batch != file.Batches[1] {
+		t.Error("BatchByNumber did not return batch number 2")
+	}
+	if _, ok := file.BatchByNumber(3); ok {
+		t.Error("BatchByNumber found batch number 3")
+	}
+}
+
+func TestFileRemoveBatch(t *testing.T) {
+	file := mockFilePPD()
+	file.AddBatch(mockBatchPPD())
+	if err := file.Create(); err != nil {
+		t.Fatalf("%T: %s", err, err)
+	}
+	second := file.Batches[1]
+	if err := file.RemoveBatch(0); err != nil {
+		t.Fatalf("%T: %s", err, err)
+	}
+	if len(file.Batches) != 1 || file.Batches[0] != second {
+		t.Fatal("RemoveBatch did not remove the first batch")
+	}
+	if err := file.Validate(); err == nil {
+		t.Error("expected File Control to be reset")
+	}
+	if err := file.Create(); err != nil {
+		t.Fatalf("%T: %s", err, err)
+	}
+	if err := file.Validate(); err != nil {
+		t.Errorf("%T: %s", err, err)
+	}
+	if file.Control.BatchCount != 1 || file.Control.TotalCreditEntryDollarAmountInFile != 100000000 {
+		t.Errorf("unexpected File Control %v", file.Control.String())
+	}
+
+	if err := file.RemoveBatch(0); err != nil {
+		t.Fatalf("%T: %s", err, err)
+	}
+	if err := file.Validate(); err != nil {
+		t.Errorf("%T: %s", err, err)
+	}
+	if file.Control.BatchCount != 0 || file.Control.BlockCount != 1 {
+		t.Errorf("unexpected File Control %v", file.Control.String())
+	}
+}
+
+func TestFileReplaceBatch(t *testing.T) {
+	file := mockFilePPD()
+	batch := mockBatchPPD()
+	batch.GetEntries()[0].Amount = 500
+	if err := batch.Create(); err != nil {
+		t.Fatalf("%T: %s", err, err)
+	}
+	if err := file.ReplaceBatch(0, batch); err != nil {
+		t.Fatalf("%T: %s", err, err)
+	}
+	if err := file.Create(); err != nil {
+		t.Fatalf("%T: %s", err, err)
+	}
+	if file.Control.TotalCreditEntryDollarAmountInFile != 500 {
+		t.Errorf("TotalCreditEntryDollarAmountInFile Expected 500 got: %v", file.Control.TotalCreditEntryDollarAmountInFile)
+	}
+	if err := file.ReplaceBatch(0, nil); err == nil {
+		t.Error("expected error replacing batch with nil")
+	}
+}
+
+func TestFileBatchIndex(t *testing.T) {
+	file := mockFilePPD()
+	for _, index := range []int{-1, 1} {
+		if err := file.RemoveBatch(index); err != nil {
+			if e, ok := err.(*FileError); ok {
+				if e.FieldName != "Batches" {
+					t.Errorf("%T: %s", err, err)
+				}
+			} else {
+				t.Errorf("expected FileError got: %v", err)
+			}
+		} else {
+			t.Errorf("expected error removing batch %d", index)
+		}
+		if err := file.ReplaceBatch(index, mockBatchPPD()); err == nil {
+			t.Errorf("expected error replacing batch %d", index)
+		}
+	}
+	if len(file.Batches) != 1 {
+		t.Error("out of range index changed the batches")
+	}
+}
+
+func TestFileOriginODFIMismatches(t *testing.T) {
+	file := mockFilePPD()
+	file.Header.ImmediateOrigin = 62000019
+	if errs := file.OriginODFIMismatches(); len(errs) != 0 {
+		t.Errorf("OriginODFIMismatches Expected none got: %v", errs)
+	}
+
+	batch := mockBatchPPD()
+	batch.GetHeader().ODFIIdentification = 23138010
+	batch.GetEntries()[0].TraceNumber = 0
+	if err := batch.Create(); err != nil {
+		t.Fatalf("%T: %s", err, err)
+	}
+	file.AddBatch(batch)
+	if err := file.Create(); err != nil {
+		t.Fatalf("%T: %s", err, err)
+	}
+	errs := file.OriginODFIMismatches()
+	if len(errs) != 2 {
+		t.Fatalf("OriginODFIMismatches Expected 2 got: %v", errs)
+	}
+	for i, fieldName := range []string{"ODFIIdentification", "TraceNumber"} {
+		if e, ok := errs[i].(*BatchError); ok {
+			if e.BatchNumber != 2 || e.FieldName != fieldName {
+				t.Errorf("%T: %s", e, e)
+			}
+		} else {
+			t.Errorf("expected BatchError got: %v", errs[i])
+		}
+	}
+}
+
+func TestFileBatchCountPolicy(t *testing.T) {
+	file := mockFilePPD()
+	file.AddBatch(mockBatchPPD())
+	if file.BatchCount() != 2 {
+		t.Errorf("BatchCount Expected 2 got: %v", file.BatchCount())
+	}
+}
+
+func TestFileRedactForLog(t *testing.T) {
+	file := mockFilePPD()
+	s := file.RedactForLog()
+	if strings.Contains(s, "\n") {
+		t.Error("RedactForLog is not a single line")
+	}
+	entry := file.Batches[0].GetEntries()[0]
+	for _, pii := range []string{entry.DFIAccountNumber, entry.IndividualName} {
+		if strings.Contains(s, pii) {
+			t.Errorf("RedactForLog contains %q", pii)
+		}
+	}
+	for _, want := range []string{"sec=PPD", "odfi=06200001", "rdfi=009101298:1", "credit=100000000"} {
+		if !strings.Contains(s, want) {
+			t.Errorf("RedactForLog Expected %q in: %v", want, s)
+		}
+	}
+
+	// each routing number is listed once with its number of entries
+	file.Batches[0].AddEntry(mockEntryDetail())
+	file.Batches[0].AddEntry(mockEntryDetail())
+	if s := file.RedactForLog(); !strings.HasSuffix(s, "rdfi=009101298:3") {
+		t.Errorf("RedactForLog Expected one routing number with 3 entries in: %v", s)
+	}
+}
+
+func TestFileEachBatch(t *testing.T) {
+	file := mockFilePPD()
+	file.AddBatch(mockBatchPPD())
+	file.AddBatch(mockBatchPPD())
+	if err := file.Create(); err != nil {
+		t.Fatalf("%T: %s", err, err)
+	}
+	count := 0
+	err := file.EachBatch(func(batch Batcher) error {
+		count++
+		if batch.GetHeader().BatchNumber == 2 {
+			return &BatchError{BatchNumber: 2, FieldName: "mock", Msg: "stop"}
+		}
+		return nil
+	})
+	if e, ok := err.(*BatchError); !ok || e.BatchNumber != 2 {
+		t.Errorf("%T: %s", err, err)
+	}
+	if count != 2 {
+		t.Errorf("EachBatch Expected 2 calls got: %v", count)
+	}
+}
+
+func TestFileSuspiciousEntries(t *testing.T) {
+	file := mockFilePPD()
+	if len(file.SuspiciousEntries(100000000)) != 0 {
+		t.Error("SuspiciousEntries Expected no entries")
+	}
+	if len(file.SuspiciousEntries(99999999)) != 1 {
+		t.Error("SuspiciousEntries Expected entry over maxAmount")
+	}
+	entry := file.Batches[0].GetEntries()[0]
+	entry.Amount = 0
+	if len(file.SuspiciousEntries(100000000)) != 1 {
+		t.Error("SuspiciousEntries Expected zero amount credit")
+	}
+	// prenote
+	entry.TransactionCode = 23
+	if len(file.SuspiciousEntries(100000000)) != 0 {
+		t.Error("SuspiciousEntries Expected zero amount prenote to be allowed")
+	}
+	entry.Amount = -100
+	if len(file.SuspiciousEntries(100000000)) != 1 {
+		t.Error("SuspiciousEntries Expected negative amount")
+	}
+}
+
+func TestFileValidateWithDuplicateBatchHeaders(t *testing.T) {
+	file := mockFilePPD()
+	file.AddBatch(mockBatchPPD())
+	if err := file.Create(); err != nil {
+		t.Fatalf("%T: %s", err, err)
+	}
+	if err := file.ValidateWith(&ValidateOpts{}); err != nil {
+		t.Errorf("%T: %s", err, err)
+	}
+	opts := &ValidateOpts{WarnOnDuplicateBatchHeaders: true}
+	err := file.ValidateWith(opts)
+	if e, ok := err.(*BatchError); ok {
+		if e.FieldName != "BatchHeader" || e.BatchNumber != 2 {
+			t.Errorf("%T: %s", err, err)
+		}
+	} else {
+		t.Errorf("expected BatchError got: %v", err)
+	}
+	file.Batches[1].GetHeader().CompanyEntryDescription = "BONUS"
+	if err := file.ValidateWith(opts); err != nil {
+		t.Errorf("%T: %s", err, err)
+	}
+}
+
+func TestFileValidateWithUniqueBatchNumbers(t *testing.T) {
+	file := mockFilePPD()
+	file.AddBatch(mockBatchPPD())
+	if err := file.Create(); err != nil {
+		t.Fatalf("%T: %s", err, err)
+	}
+	opts := &ValidateOpts{RequireUniqueBatchNumbers: true}
+	if err := file.ValidateWith(opts); err != nil {
+		t.Errorf("%T: %s", err, err)
+	}
+	file.Batches[1].GetHeader().BatchNumber = 1
+	err := file.ValidateWith(opts)
+	if e, ok := err.(*BatchError); ok {
+		if e.FieldName != "BatchNumber" {
+			t.Errorf("%T: %s", err, err)
+		}
+	} else {
+		t.Errorf("expected BatchError got: %v", err)
+	}
+}
+
+func TestFileFormattedTotals(t *testing.T) {
+	file := mockFilePPD()
+	if err := file.Create(); err != nil {
+		t.Fatalf("%T: %s", err, err)
+	}
+	if got := file.FormattedTotalCredit(); got != "$1,000,000.00" {
+		t.Errorf("FormattedTotalCredit got: %v", got)
+	}
+	if got := file.FormattedTotalDebit(); got != "$0.00" {
+		t.Errorf("FormattedTotalDebit got: %v", got)
+	}
+	if got := file.Batches[0].GetEntries()[0].FormattedAmount(); got != "$1,000,000.00" {
+		t.Errorf("FormattedAmount got: %v", got)
+	}
+}
+
+func TestFileHasAddenda(t *testing.T) {
+	file := mockFilePPD()
+	if file.HasAddenda() {
+		t.Error("HasAddenda expected false for a file without addenda")
+	}
+	file.Batches[0].GetEntries()[0].AddAddenda(mockAddenda())
+	if !file.HasAddenda() {
+		t.Error("HasAddenda expected true for a file with addenda")
+	}
+}
+
+func TestFileValidateWithSegregatedPrenotes(t *testing.T) {
+	file := mockFilePPD()
+	entry := mockEntryDetail()
+	entry.TransactionCode = 23
+	entry.Amount = 0
+	file.Batches[0].AddEntry(entry)
+	file.Batches[0].GetHeader().OriginatorStatusCode = 2
+	if err := file.Batches[0].Create(); err != nil {
+		t.Fatalf("%T: %s", err, err)
+	}
+	if err := file.Create(); err != nil {
+		t.Fatalf("%T: %s", err, err)
+	}
+	if err := file.ValidateWith(&ValidateOpts{}); err != nil {
+		t.Errorf("%T: %s", err, err)
+	}
+	err := file.ValidateWith(&ValidateOpts{RequireSegregatedPrenotes: true})
+	if e, ok := err.(*BatchError); ok {
+		if e.FieldName != "TransactionCode" {
+			t.Errorf("%T: %s", err, err)
+		}
+	} else {
+		t.Errorf("expected BatchError got: %v", err)
+	}
+}
+
+func TestFileEntryRows(t *testing.T) {
+	file := mockFilePPD()
+	rows := file.EntryRows()
+	if len(rows) != 1 {
+		t.Fatalf("EntryRows expected 1 row got: %d", len(rows))
+	}
+	entry := file.Batches[0].GetEntries()[0]
+	want := []string{"234567890", "PPD", entry.TraceNumberField(), "009101298", "123456789", "100000000", "Wade Arnold", "Forward", ""}
+	if len(rows[0]) != len(EntryColumns()) {
+		t.Errorf("EntryRows row has %d columns expected %d", len(rows[0]), len(EntryColumns()))
+	}
+	for i := range want {
+		if rows[0][i] != want[i] {
+			t.Errorf("EntryRows column %s got: %q want: %q", EntryColumns()[i], rows[0][i], want[i])
+		}
+	}
+}
+
+func TestFileValidateWithImmediateNames(t *testing.T) {
+	file := mockFilePPD()
+	opts := &ValidateOpts{RequireImmediateNames: true}
+	if err := file.ValidateWith(opts); err != nil {
+		t.Errorf("%T: %s", err, err)
+	}
+	for _, field := range []string{"ImmediateDestinationName", "ImmediateOriginName"} {
+		file := mockFilePPD()
+		if field == "ImmediateDestinationName" {
+			file.Header.ImmediateDestinationName = ""
+		} else {
+			file.Header.ImmediateOriginName = " "
+		}
+		if err := file.Validate(); err != nil {
+			t.Errorf("%T: %s", err, err)
+		}
+		err := file.ValidateWith(opts)
+		if e, ok := err.(*FieldError); ok {
+			if e.FieldName != field {
+				t.Errorf("%T: %s", err, err)
+			}
+		} else {
+			t.Errorf("expected FieldError got: %v", err)
+		}
+	}
+}
+
+func TestFileSplitByLineCount(t *testing.T) {
+	file := mockFilePPD()
+	file.Batches[0].(*BatchPPD).SetRequireUniformCategory(true)
+	for i := 0; i < 9; i++ {
+		entry := mockEntryDetail()
+		entry.AddAddenda(mockAddenda())
+		file.Batches[0].AddEntry(entry)
+	}
+	if err := file.Batches[0].Create(); err != nil {
+		t.Fatalf("%T: %s", err, err)
+	}
+	if err := file.Create(); err != nil {
+		t.Fatalf("%T: %s", err, err)
+	}
+	original := file.Batches[0].GetEntries()[1].String()
+	// 1 entry and 9 entries with an addenda in files padded to 10 lines
+	files, err := file.SplitByLineCount(19)
+	if err != nil {
+		t.Fatalf("%T: %s", err, err)
+	}
+	if len(files) != 4 {
+		t.Fatalf("SplitByLineCount expected 4 files got: %d", len(files))
+	}
+	entries, credit := 0, 0
+	for _, f := range files {
+		if err := f.Validate(); err != nil {
+			t.Errorf("%T: %s", err, err)
+		}
+		if f.LineCount(BlockingFactor) > 19 {
+			t.Errorf("SplitByLineCount file has %d lines", f.LineCount(BlockingFactor))
+		}
+		for _, batch := range f.Batches {
+			entries += len(batch.GetEntries())
+			if !batch.(*BatchPPD).requireUniformCategory {
+				t.Error("SplitByLineCount did not keep the batch options")
+			}
+			for _, entry := range batch.GetEntries() {
+				for _, e := range file.Batches[0].GetEntries() {
+					if entry == e {
+						t.Fatal("SplitByLineCount shares entries with the file")
+					}
+				}
+			}
+		}
+		credit += f.Control.TotalCreditEntryDollarAmountInFile
+	}
+	if entries != 10 || credit != file.Control.TotalCreditEntryDollarAmountInFile {
+		t.Errorf("SplitByLineCount got %d entries and credit %d", entries, credit)
+	}
+	if file.Batches[0].GetEntries()[1].String() != original {
+		t.Error("SplitByLineCount changed the entries of the file")
+	}
+
+	_, err = file.SplitByLineCount(5)
+	if e, ok := err.(*FileError); ok {
+		if e.FieldName != "TraceNumber" {
+			t.Errorf("%T: %s", err, err)
+		}
+	} else {
+		t.Errorf("expected FileError got: %v", err)
+	}
+
+	// the Addenda98 of a COR entry is a line of the file
+	cor := NewFile().SetHeader(mockFileHeader())
+	cor.AddBatch(mockBatchCOR())
+	if err := cor.Create(); err != nil {
+		t.Fatalf("%T: %s", err, err)
+	}
+	_, err = cor.SplitByLineCount(5)
+	if e, ok := err.(*FileError); ok {
+		if e.FieldName != "TraceNumber" {
+			t.Errorf("%T: %s", err, err)
+		}
+	} else {
+		t.Errorf("expected FileError for COR entry got: %v", err)
+	}
+}
+
+func TestFileValidateWithMaxAddendaPerFile(t *testing.T) {
+	file := mockFilePPD()
+	file.Batches[0].GetEntries()[0].AddAddenda(mockAddenda())
+	if err := file.Batches[0].Create(); err != nil {
+		t.Fatalf("%T: %s", err, err)
+	}
+	if err := file.Create(); err != nil {
+		t.Fatalf("%T: %s", err, err)
+	}
+	if err := file.ValidateWith(&ValidateOpts{MaxAddendaPerFile: 1}); err != nil {
+		t.Errorf("%T: %s", err, err)
+	}
+	file.AddBatch(mockBatchPPD())
+	file.Batches[1].GetEntries()[0].AddAddenda(mockAddenda())
+	if err := file.Batches[1].Create(); err != nil {
+		t.Fatalf("%T: %s", err, err)
+	}
+	if err := file.Create(); err != nil {
+		t.Fatalf("%T: %s", err, err)
+	}
+	err := file.ValidateWith(&ValidateOpts{MaxAddendaPerFile: 1})
+	if e, ok := err.(*FileError); ok {
+		if e.FieldName != "AddendaCount" || e.Value != "2" {
+			t.Errorf("%T: %s", err, err)
+		}
+	} else {
+		t.Errorf("expected FileError got: %v", err)
+	}
+}
+
+func TestFileMixedADV(t *testing.T) {
+	file := mockFilePPD()
+	file.AddBatch(mockBatchPPD())
+	file.Batches[1].GetHeader().ServiceClassCode = 280
+	if err := file.Batches[1].Create(); err != nil {
+		t.Fatalf("%T: %s", err, err)
+	}
+	if err := file.Create(); err != nil {
+		t.Fatalf("%T: %s", err, err)
+	}
+	err := file.Validate()
+	if e, ok := err.(*BatchError); ok {
+		if e.FieldName != "ServiceClassCode" || e.BatchNumber != 2 {
+			t.Errorf("%T: %s", err, err)
+		}
+	} else {
+		t.Errorf("expected BatchError got: %v", err)
+	}
+
+	file.Batches[0].GetHeader().ServiceClassCode = 280
+	if err := file.Batches[0].Create(); err != nil {
+		t.Fatalf("%T: %s", err, err)
+	}
+	if err := file.Create(); err != nil {
+		t.Fatalf("%T: %s", err, err)
+	}
+	if err := file.Validate(); err != nil {
+		t.Errorf("%T: %s", err, err)
+	}
+}
+
+func TestFileDryRunCreate(t *testing.T) {
+	file := mockFilePPD()
+	file.AddBatch(mockBatchPPD())
+	if err := file.Create(); err != nil {
+		t.Fatalf("%T: %s", err, err)
+	}
+	entry := file.Batches[1].GetEntries()[0]
+	entry.Amount = 5
+	entry.Addendum = []Addenda{mockAddenda()}
+	entry.AddendaRecordIndicator = 1
+	file.Batches[1].GetHeader().CompanyName = ""
+	before := file.Batches[0].GetControl().String() + file.Batches[1].GetControl().String() + entry.String()
+
+	errs := file.DryRunCreate()
+	if len(errs) != 2 {
+		t.Fatalf("DryRunCreate expected 2 errors got: %d", len(errs))
+	}
+	if errs[0] != nil {
+		t.Errorf("%T: %s", errs[0], errs[0])
+	}
+	if e, ok := errs[1].(*FieldError); ok {
+		if e.FieldName != "CompanyName" {
+			t.Errorf("%T: %s", e, e)
+		}
+	} else {
+		t.Errorf("expected FieldError got: %v", errs[1])
+	}
+	after := file.Batches[0].GetControl().String() + file.Batches[1].GetControl().String() + entry.String()
+	if before != after || entry.Addendum[0].SequenceNumber != mockAddenda().SequenceNumber {
+		t.Error("DryRunCreate changed the file")
+	}
+}
+
+func TestFileDryRunCreateBatchOptions(t *testing.T) {
+	file := mockFilePPD()
+	file.Batches[0].(*BatchPPD).SetIdentificationNumberValidator(func(s string) error {
+		return errors.New("is not accepted")
+	})
+	if err := file.Batches[0].Create(); err == nil {
+		t.Fatal("expected error from IdentificationNumber validator")
+	}
+	errs := file.DryRunCreate()
+	if len(errs) != 1 {
+		t.Fatalf("DryRunCreate expected 1 error got: %d", len(errs))
+	}
+	if e, ok := errs[0].(*BatchError); ok {
+		if e.FieldName != "IdentificationNumber" {
+			t.Errorf("%T: %s", e, e)
+		}
+	} else {
+		t.Errorf("expected BatchError got: %v", errs[0])
+	}
+}
+
+func TestFileEntryHashByRouting(t *testing.T) {
+	file := mockFilePPD()
+	file.AddBatch(mockBatchPPD())
+	entry := mockEntryDetail()
+	entry.SetRDFI(231380104)
+	file.Batches[1].AddEntry(entry)
+	if err := file.Batches[1].Create(); err != nil {
+		t.Fatalf("%T: %s", err, err)
+	}
+	if err := file.Create(); err != nil {
+		t.Fatalf("%T: %s", err, err)
+	}
+	hashes := file.EntryHashByRouting()
+	if len(hashes) != 2 || hashes["009101298"] != 2*910129 || hashes["231380104"] != 23138010 {
+		t.Errorf("EntryHashByRouting got: %v", hashes)
+	}
+	sum := 0
+	for _, hash := range hashes {
+		sum += hash
+	}
+	if sum != file.Control.EntryHash {
+		t.Errorf("EntryHashByRouting sum %d does not equal file control %d", sum, file.Control.EntryHash)
+	}
+}
+
+func TestFileValidateWithReturnCodeEntryType(t *testing.T) {
+	file := mockFilePPD()
+	entry := file.Batches[0].GetEntries()[0]
+	entry.TransactionCode = 26
+	entry.AddReturnAddenda(ReturnAddenda{ReturnCode: "R01"})
+	opts := &ValidateOpts{RequireReturnCodeEntryType: true}
+	if err := file.ValidateWith(opts); err != nil {
+		t.Errorf("%T: %s", err, err)
+	}
+	entry.TransactionCode = 21
+	err := file.ValidateWith(opts)
+	if e, ok := err.(*BatchError); ok {
+		if e.FieldName != "ReturnCode" {
+			t.Errorf("%T: %s", err, err)
+		}
+	} else {
+		t.Errorf("expected BatchError got: %v", err)
+	}
+	entry.ReturnAddendum[0].ReturnCode = "R03"
+	if err := file.ValidateWith(opts); err != nil {
+		t.Errorf("%T: %s", err, err)
+	}
+}
+
+func TestFileValidateWithPaymentDelimiters(t *testing.T) {
+	file := mockFilePPD()
+	addenda := mockAddenda()
+	addenda.PaymentRelatedInformation = "RMR*IV*0123456789"
+	file.Batches[0].GetEntries()[0].AddAddenda(addenda)
+	if err := file.Validate(); err != nil {
+		t.Errorf("%T: %s", err, err)
+	}
+	opts := &ValidateOpts{RejectPaymentDelimiters: true}
+	err := file.ValidateWith(opts)
+	if e, ok := err.(*BatchError); ok {
+		if e.FieldName != "PaymentRelatedInformation" {
+			t.Errorf("%T: %s", err, err)
+		}
+	} else {
+		t.Errorf("expected BatchError got: %v", err)
+	}
+	file.Batches[0].GetEntries()[0].Addendum[0].PaymentRelatedInformation = "C:\\PAYROLL"
+	if err := file.ValidateWith(opts); err == nil {
+		t.Error("expected PaymentRelatedInformation error")
+	}
+	file.Batches[0].GetEntries()[0].Addendum[0].PaymentRelatedInformation = "INVOICE 0123456789"
+	if err := file.ValidateWith(opts); err != nil {
+		t.Errorf("%T: %s", err, err)
+	}
+}
+
+// TestFileValidateWithPaymentDelimitersSEC CTX, ENR and TRX addenda may contain the delimiters
+func TestFileValidateWithPaymentDelimitersSEC(t *testing.T) {
+	for _, batch := range []Batcher{mockBatchCTX(), mockBatchENR(), mockBatchTRX()} {
+		batch.GetEntries()[0].Addendum[0].PaymentRelatedInformation = "RMR*IV*0123456789\\"
+		file := NewFile().SetHeader(mockFileHeader())
+		file.AddBatch(batch)
+		if err := file.isPaymentDelimiters(); err != nil {
+			t.Errorf("%s: %T: %s", batch.GetHeader().StandardEntryClassCode, err, err)
+		}
+	}
+}
+
+func TestFileAsFieldError(t *testing.T) {
+	file := NewFile().SetHeader(mockFileHeader())
+	file.AddBatch(mockBatchPPD())
+	file.AddBatch(mockBatchADV())
+	if err := file.Create(); err != nil {
+		t.Fatalf("%T: %s", err, err)
+	}
+	loc, ok := AsFieldError(file.Validate())
+	if !ok || loc.FieldName != "ServiceClassCode" || loc.BatchIndex != 1 || loc.EntryIndex != -1 {
+		t.Errorf("unexpected location %+v", loc)
+	}
+
+	file = mockFilePPD()
+	file.AddBatch(mockBatchPPD())
+	batch := file.Batches[1]
+	batch.AddEntry(mockEntryDetail())
+	addenda := mockAddenda()
+	addenda.PaymentRelatedInformation = "RMR*IV*0123456789"
+	batch.GetEntries()[1].AddAddenda(addenda)
+	if err := batch.Create(); err != nil {
+		t.Fatalf("%T: %s", err, err)
+	}
+	if err := file.Create(); err != nil {
+		t.Fatalf("%T: %s", err, err)
+	}
+	loc, ok = AsFieldError(file.ValidateWith(&ValidateOpts{RejectPaymentDelimiters: true}))
+	if !ok || loc.FieldName != "PaymentRelatedInformation" || loc.BatchIndex != 1 || loc.EntryIndex != 1 {
+		t.Errorf("unexpected location %+v", loc)
+	}
+}
+
+func TestFileLineCount(t *testing.T) {
+	file := mockFilePPD()
+	if file.LineCount(BlockingFactor) != 10 {
+		t.Errorf("LineCount Expected 10 got: %v", file.LineCount(BlockingFactor))
+	}
+	// header, batch header, entry, batch control and file control records in blocks of 4
+	if file.LineCount(4) != 8 || file.LineCount(0) != 10 {
+		t.Errorf("LineCount Expected 8 and 10 got: %v and %v", file.LineCount(4), file.LineCount(0))
+	}
+	if file.ExceedsLineLimit() {
+		t.Error("ExceedsLineLimit Expected false")
+	}
+	entries := file.Batches[0].GetEntries()
+	for i := 0; i < LineLimit-4; i++ {
+		file.Batches[0].AddEntry(entries[0])
+	}
+	// 2 file, 2 batch and LineLimit-3 entry records
+	if file.LineCount(BlockingFactor) != LineLimit+10 {
+		t.Errorf("LineCount Expected %v got: %v", LineLimit+10, file.LineCount(BlockingFactor))
+	}
+	if !file.ExceedsLineLimit() {
+		t.Error("ExceedsLineLimit Expected true")
+	}
+}
+
+func TestFileSplitBy(t *testing.T) {
+	file := mockFilePPD()
+	for i := 0; i < 4; i++ {
+		file.AddBatch(mockBatchPPD())
+	}
+	if err := file.Create(); err != nil {
+		t.Fatalf("%T: %s", err, err)
+	}
+	files, err := file.SplitBy(SplitOpts{MaxEntriesPerFile: 2})
+	if err != nil {
+		t.Fatalf("%T: %s", err, err)
+	}
+	if len(files) != 3 {
+		t.Fatalf("SplitBy expected 3 files got: %d", len(files))
+	}
+	for i, f := range files {
+		if err := f.Validate(); err != nil {
+			t.Errorf("%T: %s", err, err)
+		}
+		if f.Header.FileIDModifier != fileIDModifiers[i:i+1] {
+			t.Errorf("file %d FileIDModifier %q", i, f.Header.FileIDModifier)
+		}
+		for n, batch := range f.Batches {
+			if batch.GetHeader().BatchNumber != n+1 {
+				t.Errorf("file %d batch %d has BatchNumber %d", i, n, batch.GetHeader().BatchNumber)
+			}
+		}
+	}
+	if files[2].Batches[0].GetEntries()[0].TraceNumber != file.Batches[4].GetEntries()[0].TraceNumber {
+		t.Error("SplitBy changed the trace number")
+	}
+	if file.Batches[4].GetHeader().BatchNumber != 5 {
+		t.Error("SplitBy changed the original file")
+	}
+
+	files, err = file.SplitBy(SplitOpts{MaxBatchesPerFile: 4})
+	if err != nil {
+		t.Fatalf("%T: %s", err, err)
+	}
+	if len(files) != 2 || len(files[1].Batches) != 1 {
+		t.Errorf("SplitBy expected 2 files got: %d", len(files))
+	}
+
+	_, err = file.SplitBy(SplitOpts{MaxEntriesPerFile: 0})
+	if err != nil {
+		t.Errorf("%T: %s", err, err)
+	}
+
+	file.Batches[1].GetEntries()[0].AddAddenda(mockAddenda())
+	if err := file.Batches[1].Create(); err != nil {
+		t.Fatalf("%T: %s", err, err)
+	}
+	_, err = file.SplitBy(SplitOpts{MaxEntriesPerFile: 1})
+	if e, ok := err.(*BatchError); ok {
+		if e.BatchNumber != 2 || e.FieldName != "EntryAddendaCount" {
+			t.Errorf("%T: %s", err, err)
+		}
+	} else {
+		t.Errorf("expected BatchError got: %v", err)
+	}
+}
+
+func TestFileResolveTraceCollisions(t *testing.T) {
+	file := mockFilePPD()
+	file.AddBatch(mockBatchPPD())
+	file.AddBatch(mockBatchPPD())
+	if err := file.Create(); err != nil {
+		t.Fatalf("%T: %s", err, err)
+	}
+	if n := file.ResolveTraceCollisions(); n != 2 {
+		t.Errorf("ResolveTraceCollisions expected 2 got: %d", n)
+	}
+	traces := make(map[int]bool)
+	for _, batch := range file.Batches {
+		entry := batch.GetEntries()[0]
+		if traces[entry.TraceNumber] {
+			t.Errorf("duplicate trace number %v", entry.TraceNumberField())
+		}
+		traces[entry.TraceNumber] = true
+		if entry.TraceNumberField()[:8] != batch.GetHeader().ODFIIdentificationField() {
+			t.Errorf("trace number %v does not start with ODFI", entry.TraceNumberField())
+		}
+	}
+	if n := file.ResolveTraceCollisions(); n != 0 {
+		t.Errorf("ResolveTraceCollisions expected 0 got: %d", n)
+	}
+	if err := file.Create(); err != nil {
+		t.Errorf("%T: %s", err, err)
+	}
+}
+
+// TestFileResolveTraceCollisionsOrder ensures a renumbered batch stays in ascending trace order and
+// its addenda follow the new trace numbers
+func TestFileResolveTraceCollisionsOrder(t *testing.T) {
+	file := mockFilePPD()
+	batch := NewBatchPPD()
+	batch.SetHeader(mockBatchHeader())
+	for i := 0; i < 3; i++ {
+		batch.AddEntry(mockEntryDetail())
+	}
+	batch.GetEntries()[0].AddAddenda(mockAddenda())
+	if err := batch.GetEntries()[1].AddDishonoredReturn("R69", 62000010000001, "09101298", mockDishonoredReturn()); err != nil {
+		t.Fatalf("%T: %s", err, err)
+	}
+	batch.GetEntries()[1].Category = CategoryForward
+	if err := batch.Create(); err != nil {
+		t.Fatalf("%T: %s", err, err)
+	}
+	file.AddBatch(batch)
+	if err := file.Create(); err != nil {
+		t.Fatalf("%T: %s", err, err)
+	}
+	// traces 1, 2 and 3 follow a batch with trace 1 and become 4, 5 and 6
+	if n := file.ResolveTraceCollisions(); n != 1 {
+		t.Errorf("ResolveTraceCollisions expected 1 got: %d", n)
+	}
+	entries := file.Batches[1].GetEntries()
+	for i, entry := range entries {
+		if entry.TraceNumber != file.Batches[0].GetEntries()[0].TraceNumber+3+i {
+			t.Errorf("entry %d trace number %v", i, entry.TraceNumberField())
+		}
+	}
+	if seq := entries[0].Addendum[0].EntryDetailSequenceNumberField(); seq != entries[0].TraceNumberField()[8:] {
+		t.Errorf("addenda EntryDetailSequenceNumber %v for trace number %v", seq, entries[0].TraceNumberField())
+	}
+	if trace := entries[1].ReturnAddendum[0].Trace; trace != entries[1].TraceNumber {
+		t.Errorf("return addenda trace %v for trace number %v", trace, entries[1].TraceNumber)
+	}
+	for _, batch := range file.Batches {
+		if err := batch.Validate(); err != nil {
+			t.Errorf("%T: %s", err, err)
+		}
+	}
+}
+
+func TestFileEffectiveEntryDates(t *testing.T) {
+	file := mockFilePPD()
+	file.Batches[0].GetHeader().EffectiveEntryDate = time.Time{}
+	for _, day := range []int{3, 0, 1, 3} {
+		batch := mockBatchPPD()
+		batch.GetHeader().EffectiveEntryDate = time.Time{}
+		if day > 0 {
+			batch.GetHeader().EffectiveEntryDate = time.Date(2017, time.November, day, 0, 0, 0, 0, time.UTC)
+		}
+		file.AddBatch(batch)
+	}
+	dates := file.EffectiveEntryDates()
+	if len(dates) != 2 || dates[0].Day() != 1 || dates[1].Day() != 3 {
+		t.Errorf("EffectiveEntryDates got: %v", dates)
+	}
+}
+
+func TestFileIsControlStale(t *testing.T) {
+	file := mockFilePPD()
+	file.AddBatch(mockBatchPPD())
+	if err := file.Create(); err != nil {
+		t.Fatalf("%T: %s", err, err)
+	}
+	if file.IsControlStale() {
+		t.Error("IsControlStale expected false after Create")
+	}
+	entry := file.Batches[1].GetEntries()[0]
+	entry.Amount = 5
+	if !file.IsControlStale() {
+		t.Error("IsControlStale expected true after changing an amount")
+	}
+	if err := file.Batches[1].Create(); err != nil {
+		t.Fatalf("%T: %s", err, err)
+	}
+	if !file.IsControlStale() {
+		t.Error("IsControlStale expected true with a stale file control")
+	}
+	if err := file.Create(); err != nil {
+		t.Fatalf("%T: %s", err, err)
+	}
+	if file.IsControlStale() {
+		t.Error("IsControlStale expected false after Create")
+	}
+}
+
+// TestFileIsControlStalePreserved preserved batch numbers and reserved fields are not stale
+func TestFileIsControlStalePreserved(t *testing.T) {
+	fixture, err := ioutil.ReadFile("./testdata/ppd-batch-number-100.ach")
+	if err != nil {
+		t.Fatal(err)
+	}
+	file, err := NewReader(bytes.NewReader(fixture), PreserveReserved()).Read()
+	if err != nil {
+		t.Fatalf("%T: %s", err, err)
+	}
+	if err := file.CreateWith(FileOpts{PreserveBatchNumbers: true}); err != nil {
+		t.Fatalf("%T: %s", err, err)
+	}
+	file.Control.reserved = "reserved"
+	if file.IsControlStale() {
+		t.Error("IsControlStale expected false with preserved batch numbers and reserved field")
+	}
+}
+
+func TestAssignFileIDModifiers(t *testing.T) {
+	var files []*File
+	for i := 0; i < 37; i++ {
+		files = append(files, mockFilePPD())
+	}
+	err := AssignFileIDModifiers(files)
+	if e, ok := err.(*FileError); ok {
+		if e.FieldName != "FileIDModifier" || files[0].Header.FileIDModifier != "A" || files[1].Header.FileIDModifier != "A" {
+			t.Errorf("%T: %s", err, err)
+		}
+	} else {
+		t.Errorf("expected FileError got: %v", err)
+	}
+
+	files = files[:36]
+	if err := AssignFileIDModifiers(files); err != nil {
+		t.Fatalf("%T: %s", err, err)
+	}
+	seen := make(map[string]bool)
+	for _, f := range files {
+		if seen[f.Header.FileIDModifier] {
+			t.Errorf("duplicate FileIDModifier %v", f.Header.FileIDModifier)
+		}
+		seen[f.Header.FileIDModifier] = true
+		if err := f.Header.Validate(); err != nil {
+			t.Errorf("%T: %s", err, err)
+		}
+	}
+	if files[1].Header.FileIDModifier != "B" || files[35].Header.FileIDModifier != "9" {
+		t.Errorf("unexpected FileIDModifier sequence %v %v", files[1].Header.FileIDModifier, files[35].Header.FileIDModifier)
+	}
+}
+
+func TestFileNextFileIDModifier(t *testing.T) {
+	file := mockFilePPD()
+	modifier, err := file.NextFileIDModifier([]string{"A", "b", "D"})
+	if err != nil {
+		t.Fatalf("%T: %s", err, err)
+	}
+	if modifier != "C" || file.Header.FileIDModifier != "C" {
+		t.Errorf("NextFileIDModifier got %v", modifier)
+	}
+
+	var used []string
+	for i := range fileIDModifiers {
+		used = append(used, fileIDModifiers[i:i+1])
+	}
+	_, err = file.NextFileIDModifier(used)
+	if e, ok := err.(*FileError); ok {
+		if e.FieldName != "FileIDModifier" || file.Header.FileIDModifier != "C" {
+			t.Errorf("%T: %s", err, err)
+		}
+	} else {
+		t.Errorf("expected FileError got: %v", err)
+	}
+}
+
+func TestFileCreateWithGlobalTraceSequencing(t *testing.T) {
+	file := mockFilePPD()
+	batch := mockBatchPPD()
+	entry := mockEntryDetail()
+	entry.AddAddenda(mockAddenda())
+	batch.AddEntry(entry)
+	if err := batch.Create(); err != nil {
+		t.Fatalf("%T: %s", err, err)
+	}
+	file.AddBatch(batch)
+	if err := file.CreateWith(FileOpts{}); err != nil {
+		t.Fatalf("%T: %s", err, err)
+	}
+	if file.ResolveTraceCollisions() == 0 {
+		t.Fatal("expected per batch trace numbers to collide")
+	}
+
+	file = mockFilePPD()
+	file.AddBatch(batch)
+	if err := file.CreateWith(FileOpts{GlobalTraceSequencing: true}); err != nil {
+		t.Fatalf("%T: %s", err, err)
+	}
+	seq := 0
+	for _, b := range file.Batches {
+		for _, entry := range b.GetEntries() {
+			seq++
+			if entry.TraceNumber != 6200001*10000000+seq {
+				t.Errorf("entry %d TraceNumber %s", seq, entry.TraceNumberField())
+			}
+			for _, addenda := range entry.Addendum {
+				if addenda.EntryDetailSequenceNumber != seq {
+					t.Errorf("entry %d addenda EntryDetailSequenceNumber %d", seq, addenda.EntryDetailSequenceNumber)
+				}
+			}
+		}
+	}
+	if seq != 3 {
+		t.Errorf("expected 3 entries got: %d", seq)
+	}
+	if err := file.Validate(); err != nil {
+		t.Errorf("%T: %s", err, err)
+	}
+
+	// return addenda and Addenda98 carry the trace number of their entry
+	file = mockFilePPD()
+	file.Batches[0].GetEntries()[0].AddReturnAddenda(ReturnAddenda{ReturnCode: "R01"})
+	if err := file.Batches[0].Create(); err != nil {
+		t.Fatalf("%T: %s", err, err)
+	}
+	file.AddBatch(mockBatchCOR())
+	if err := file.CreateWith(FileOpts{GlobalTraceSequencing: true}); err != nil {
+		t.Fatalf("%T: %s", err, err)
+	}
+	returned := file.Batches[0].GetEntries()[0]
+	if returned.ReturnAddendum[0].Trace != returned.TraceNumber {
+		t.Errorf("ReturnAddenda Trace %d entry TraceNumber %d", returned.ReturnAddendum[0].Trace, returned.TraceNumber)
+	}
+	noc := file.Batches[1].GetEntries()[0]
+	if noc.TraceNumberField()[8:] != "0000002" || noc.Addenda98.Trace != noc.TraceNumber {
+		t.Errorf("Addenda98 Trace %d entry TraceNumber %d", noc.Addenda98.Trace, noc.TraceNumber)
+	}
+}
+
+func TestFileDominantSEC(t *testing.T) {
+	file := NewFile()
+	if sec := file.DominantSEC(); sec != "" {
+		t.Errorf("empty file DominantSEC %q", sec)
+	}
+	file = mockFilePPD()
+	if sec := file.DominantSEC(); sec != "PPD" {
+		t.Errorf("expected PPD got: %q", sec)
+	}
+
+	// a CCD batch ties the PPD batch and sorts first
+	file.AddBatch(mockBatchCCD())
+	if sec := file.DominantSEC(); sec != "CCD" {
+		t.Errorf("expected CCD got: %q", sec)
+	}
+	file.AddBatch(mockBatchPPD())
+	if sec := file.DominantSEC(); sec != "PPD" {
+		t.Errorf("expected PPD got: %q", sec)
+	}
+}
+
+func TestFileValidateWithForwardPrenoteReturnAddenda(t *testing.T) {
+	file := mockFilePPD()
+	entry := file.Batches[0].GetEntries()[0]
+	entry.TransactionCode = 23
+	entry.AddReturnAddenda(ReturnAddenda{ReturnCode: "R03"})
+	opts := &ValidateOpts{RejectForwardPrenoteReturnAddenda: true}
+	err := file.ValidateWith(opts)
+	if e, ok := err.(*BatchError); ok {
+		if e.FieldName != "Category" {
+			t.Errorf("%T: %s", err, err)
+		}
+	} else {
+		t.Errorf("expected BatchError got: %v", err)
+	}
+	if loc, ok := AsFieldError(err); !ok || loc.EntryIndex != 0 {
+		t.Errorf("AsFieldError %+v", loc)
+	}
+	entry.Category = CategoryReturn
+	if err := file.ValidateWith(opts); err != nil {
+		t.Errorf("%T: %s", err, err)
+	}
+	// live returns are not prenotes
+	entry.TransactionCode = 21
+	entry.Category = CategoryForward
+	if err := file.ValidateWith(opts); err != nil {
+		t.Errorf("%T: %s", err, err)
+	}
+}
+
+func TestFileValidateWithTruncatedCompanyName(t *testing.T) {
+	file := mockFilePPD()
+	file.Batches[0].GetHeader().CompanyName = "ACME Corporation"
+	opts := &ValidateOpts{RejectTruncatedCompanyName: true}
+	if err := file.ValidateWith(opts); err != nil {
+		t.Errorf("%T: %s", err, err)
+	}
+	file.Batches[0].GetHeader().CompanyName = "ACME Corporation Inc"
+	if err := file.Validate(); err != nil {
+		t.Errorf("%T: %s", err, err)
+	}
+	err := file.ValidateWith(opts)
+	if e, ok := err.(*BatchError); ok {
+		if e.FieldName != "CompanyName" {
+			t.Errorf("%T: %s", err, err)
+		}
+	} else {
+		t.Errorf("expected BatchError got: %v", err)
+	}
+}
+
+func TestFileStripAddenda(t *testing.T) {
+	file := NewFile().SetHeader(mockFileHeader())
+	ppd := mockBatchPPD()
+	ppd.GetEntries()[0].AddAddenda(mockAddenda())
+	if err := ppd.Create(); err != nil {
+		t.Fatalf("%T: %s", err, err)
+	}
+	file.AddBatch(ppd)
+	file.AddBatch(mockBatchCTX())
+	if err := file.Create(); err != nil {
+		t.Fatalf("%T: %s", err, err)
+	}
+
+	if n := file.StripAddenda(); n != 1+len(mockBatchCTX().GetEntries()[0].Addendum) {
+		t.Errorf("StripAddenda removed %d addenda", n)
+	}
+	if file.HasAddenda() || file.AddendaCount() != 0 {
+		t.Error("file has addenda after StripAddenda")
+	}
+	for _, batch := range file.Batches {
+		if err := batch.Create(); err != nil {
+			t.Fatalf("%T: %s", err, err)
+		}
+	}
+	if err := file.Create(); err != nil {
+		t.Fatalf("%T: %s", err, err)
+	}
+	if err := file.Validate(); err != nil {
+		t.Errorf("%T: %s", err, err)
+	}
+	if file.Control.EntryAddendaCount != 2 {
+		t.Errorf("EntryAddendaCount got %d", file.Control.EntryAddendaCount)
+	}
+}
+
+func TestFileIdempotencyKey(t *testing.T) {
+	file := mockFilePPD()
+	key := file.IdempotencyKey()
+	if len(key) != 64 {
+		t.Errorf("IdempotencyKey expected 64 hex characters got: %q", key)
+	}
+	if file.IdempotencyKey() != key {
+		t.Error("IdempotencyKey is not stable")
+	}
+
+	// a file created at another time has the same key
+	other := mockFilePPD()
+	other.Header.FileCreationDate = file.Header.FileCreationDate.AddDate(0, 0, -1)
+	other.Header.FileCreationTime = file.Header.FileCreationTime.Add(time.Hour)
+	if other.IdempotencyKey() != key {
+		t.Error("IdempotencyKey changed with the file creation date and time")
+	}
+
+	other.Batches[0].GetEntries()[0].Amount++
+	if other.IdempotencyKey() == key {
+		t.Error("IdempotencyKey did not change with the entry amount")
+	}
+	other = mockFilePPD()
+	other.Header.FileIDModifier = "B"
+	if other.IdempotencyKey() != key {
+		t.Error("IdempotencyKey changed with the FileIDModifier")
+	}
+	other.Header.ImmediateOrigin = 231380104
+	if other.IdempotencyKey() == key {
+		t.Error("IdempotencyKey did not change with the ImmediateOrigin")
+	}
+}
@@ -0,0 +1,89 @@
+// Copyright 2017 The ACH Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package ach
+
+import (
+	"math/rand"
+	"strconv"
+	"time"
+)
+
+// TestFileOpts controls the file returned by GenerateTestFile.
+type TestFileOpts struct {
+	// SEC is the Standard Entry Class code of the batch. PPD, CCD and WEB are supported and
+	// PPD is used if SEC is blank.
+	SEC string `json:"sec,omitempty"`
+	// Entries is the number of entries in the batch. One entry is generated if Entries is zero.
+	Entries int `json:"entries,omitempty"`
+	// Debits is the number of the entries that are debits. The remaining entries are credits.
+	Debits int `json:"debits,omitempty"`
+	// Seed generates the routing numbers, accounts, names and amounts of the entries. The same
+	// options always generate the same file.
+	Seed int64 `json:"seed,omitempty"`
+}
+
+// testFileDate is the FileCreationDate and EffectiveEntryDate of generated files so they do
+// not depend on the current time.
+var testFileDate = time.Date(2018, time.January, 2, 0, 0, 0, 0, time.UTC)
+
+var (
+	testFileFirstNames = []string{"Ada", "Grace", "Alan", "Edsger", "Barbara", "Donald", "Frances", "Ken"}
+	testFileLastNames  = []string{"Lovelace", "Hopper", "Turing", "Dijkstra", "Liskov", "Knuth", "Allen", "Thompson"}
+)
+
+// GenerateTestFile returns a created file with one batch of generated entries for use as a
+// test fixture. The credit entries are listed before the debit entries. The returned file
+// passes Create and Validate. nil is returned if opts.SEC is not supported.
+func GenerateTestFile(opts TestFileOpts) *File {
+	sec := opts.SEC
+	if sec == "" {
+		sec = ppd
+	}
+	if sec != ppd && sec != ccd && sec != web {
+		return nil
+	}
+	entries := opts.Entries
+	if entries <= 0 {
+		entries = 1
+	}
+	r := rand.New(rand.NewSource(opts.Seed))
+
+	fh := NewFileHeader()
+	fh.ImmediateDestination = 231380104
+	fh.ImmediateOrigin = 121042882
+	fh.FileCreationDate = testFileDate
+	fh.ImmediateDestinationName = "Federal Reserve Bank"
+	fh.ImmediateOriginName = "Test Originator"
+
+	payments := make([]Payment, entries)
+	for i := range payments {
+		payments[i] = Payment{
+			From:                    PaymentAccount{RoutingNumber: "121042882"},
+			To:                      PaymentAccount{RoutingNumber: testFileRoutingNumber(r), AccountNumber: strconv.Itoa(100000 + r.Intn(900000000))},
+			AmountCents:             1 + r.Intn(1000000),
+			Debit:                   i >= entries-opts.Debits,
+			Savings:                 r.Intn(2) == 1,
+			Name:                    testFileFirstNames[r.Intn(len(testFileFirstNames))] + " " + testFileLastNames[r.Intn(len(testFileLastNames))],
+			IdentificationNumber:    strconv.Itoa(i + 1),
+			SEC:                     sec,
+			CompanyName:             "Test Company",
+			CompanyIdentification:   "121042882",
+			CompanyEntryDescription: "TEST",
+			EffectiveEntryDate:      testFileDate,
+		}
+	}
+	file, err := BuildFile(&fh, payments)
+	if err != nil {
+		return nil
+	}
+	return file
+}
+
+// testFileRoutingNumber returns a random 9 digit routing number with a valid check digit
+func testFileRoutingNumber(r *rand.Rand) string {
+	var v validator
+	rdfi := "0" + strconv.Itoa(1000000+r.Intn(9000000))
+	return rdfi + strconv.Itoa(v.CalculateCheckDigit(rdfi))
+}
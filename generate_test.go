@@ -0,0 +1,68 @@
+// Copyright 2017 The ACH Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package ach
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestGenerateTestFile(t *testing.T) {
+	for _, sec := range []string{"", "PPD", "CCD", "WEB"} {
+		file := GenerateTestFile(TestFileOpts{SEC: sec, Entries: 25, Debits: 10, Seed: 42})
+		if file == nil {
+			t.Fatalf("%s: GenerateTestFile returned nil", sec)
+		}
+		if err := file.Validate(); err != nil {
+			t.Errorf("%s: %T: %s", sec, err, err)
+		}
+		if err := file.Batches[0].Validate(); err != nil {
+			t.Errorf("%s: %T: %s", sec, err, err)
+		}
+		debits := 0
+		for _, entry := range file.Batches[0].GetEntries() {
+			if entry.TransactionCode == 27 || entry.TransactionCode == 37 {
+				debits++
+			}
+		}
+		if len(file.Batches[0].GetEntries()) != 25 || debits != 10 {
+			t.Errorf("%s: expected 25 entries with 10 debits got %d with %d debits", sec, len(file.Batches[0].GetEntries()), debits)
+		}
+		if file.Batches[0].GetHeader().ServiceClassCode != 200 {
+			t.Errorf("%s: ServiceClassCode %d", sec, file.Batches[0].GetHeader().ServiceClassCode)
+		}
+	}
+}
+
+func TestGenerateTestFileDeterministic(t *testing.T) {
+	write := func(opts TestFileOpts) string {
+		b := &bytes.Buffer{}
+		if err := NewWriter(b).WriteAll([]*File{GenerateTestFile(opts)}); err != nil {
+			t.Fatalf("%T: %s", err, err)
+		}
+		return b.String()
+	}
+	opts := TestFileOpts{Entries: 5, Debits: 2, Seed: 7}
+	if write(opts) != write(opts) {
+		t.Error("the same options generated different files")
+	}
+	opts.Seed = 8
+	if write(opts) == write(TestFileOpts{Entries: 5, Debits: 2, Seed: 7}) {
+		t.Error("different seeds generated the same file")
+	}
+}
+
+func TestGenerateTestFileSEC(t *testing.T) {
+	if file := GenerateTestFile(TestFileOpts{SEC: "IAT"}); file != nil {
+		t.Error("expected nil for unsupported SEC")
+	}
+	file := GenerateTestFile(TestFileOpts{})
+	if file == nil || len(file.Batches[0].GetEntries()) != 1 {
+		t.Fatal("expected one entry by default")
+	}
+	if file.Batches[0].GetHeader().ServiceClassCode != 220 {
+		t.Errorf("ServiceClassCode %d", file.Batches[0].GetHeader().ServiceClassCode)
+	}
+}
@@ -0,0 +1,138 @@
+// Copyright 2017 The ACH Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package ach
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// Errors specific to merging files
+var (
+	msgMergeHeader = "%v does not match %v"
+	msgMergeTotal  = "merged %v does not equal the sum of the files %v"
+)
+
+// MergeAndVerify returns a new file with the header of a and copies of the batches of a followed
+// by the batches of b. The files must have the same ImmediateDestination and ImmediateOrigin.
+// The merged file is created and an error is returned if its total debit, total credit or entry
+// and addenda count does not equal the sum of the file controls of a and b. a and b are not changed.
+func MergeAndVerify(a, b *File) (*File, error) {
+	if a.Header.ImmediateDestination != b.Header.ImmediateDestination {
+		msg := fmt.Sprintf(msgMergeHeader, b.Header.ImmediateDestinationField(), a.Header.ImmediateDestinationField())
+		return nil, &FileError{FieldName: "ImmediateDestination", Value: b.Header.ImmediateDestinationField(), Msg: msg}
+	}
+	if a.Header.ImmediateOrigin != b.Header.ImmediateOrigin {
+		msg := fmt.Sprintf(msgMergeHeader, b.Header.ImmediateOriginField(), a.Header.ImmediateOriginField())
+		return nil, &FileError{FieldName: "ImmediateOrigin", Value: b.Header.ImmediateOriginField(), Msg: msg}
+	}
+	merged := NewFile().SetHeader(a.Header)
+	for _, f := range []*File{a, b} {
+		for _, batch := range f.Batches {
+			clone, err := cloneBatch(batch)
+			if err != nil {
+				return nil, err
+			}
+			merged.AddBatch(clone)
+		}
+	}
+	if err := merged.Create(); err != nil {
+		return nil, err
+	}
+
+	totals := []struct {
+		field          string
+		merged, inputs int
+	}{
+		{"TotalDebitEntryDollarAmountInFile", merged.Control.TotalDebitEntryDollarAmountInFile,
+			a.Control.TotalDebitEntryDollarAmountInFile + b.Control.TotalDebitEntryDollarAmountInFile},
+		{"TotalCreditEntryDollarAmountInFile", merged.Control.TotalCreditEntryDollarAmountInFile,
+			a.Control.TotalCreditEntryDollarAmountInFile + b.Control.TotalCreditEntryDollarAmountInFile},
+		{"EntryAddendaCount", merged.Control.EntryAddendaCount,
+			a.Control.EntryAddendaCount + b.Control.EntryAddendaCount},
+	}
+	for _, total := range totals {
+		if total.merged != total.inputs {
+			msg := fmt.Sprintf(msgMergeTotal, total.merged, total.inputs)
+			return nil, &FileError{FieldName: total.field, Value: strconv.Itoa(total.merged), Msg: msg}
+		}
+	}
+	return merged, nil
+}
+
+// MergeOpts controls how MergeFilesWith groups batches into files. Batches are always grouped
+// by the ImmediateDestination and ImmediateOrigin of their file.
+type MergeOpts struct {
+	// GroupByODFI merges batches with different ODFIIdentification into separate files.
+	GroupByODFI bool `json:"group_by_odfi,omitempty"`
+	// GroupByEffectiveDate merges batches with different EffectiveEntryDate into separate files.
+	GroupByEffectiveDate bool `json:"group_by_effective_date,omitempty"`
+	// MaxEntriesPerFile splits a group into files of at most MaxEntriesPerFile entry and
+	// addenda records as File.SplitBy does. Zero is no limit.
+	MaxEntriesPerFile int `json:"max_entries_per_file,omitempty"`
+}
+
+// mergeKey holds the fields batches are grouped by in MergeFilesWith.
+type mergeKey struct {
+	destination        int
+	origin             int
+	odfi               int
+	effectiveEntryDate string
+}
+
+// MergeFilesWith merges copies of the batches of files into one created file per group of
+// opts. Groups and the batches in each group are in the order they are found in files and each
+// file has the header of the first file with a batch in its group. Batch numbers and file
+// controls are calculated for each merged file and when there is more than one merged file they
+// are given FileIDModifiers A, B, C and so on. files are not changed.
+func MergeFilesWith(files []*File, opts MergeOpts) ([]*File, error) {
+	var keys []mergeKey
+	groups := make(map[mergeKey]*File)
+	for _, f := range files {
+		for _, batch := range f.Batches {
+			key := mergeKey{destination: f.Header.ImmediateDestination, origin: f.Header.ImmediateOrigin}
+			if opts.GroupByODFI {
+				key.odfi = batch.GetHeader().ODFIIdentification
+			}
+			if opts.GroupByEffectiveDate {
+				key.effectiveEntryDate = batch.GetHeader().EffectiveEntryDateField()
+			}
+			group, ok := groups[key]
+			if !ok {
+				group = NewFile().SetHeader(f.Header)
+				groups[key] = group
+				keys = append(keys, key)
+			}
+			clone, err := cloneBatch(batch)
+			if err != nil {
+				return nil, err
+			}
+			group.AddBatch(clone)
+		}
+	}
+
+	var merged []*File
+	for _, key := range keys {
+		group := groups[key]
+		if err := group.Create(); err != nil {
+			return nil, err
+		}
+		if opts.MaxEntriesPerFile <= 0 {
+			merged = append(merged, group)
+			continue
+		}
+		split, err := group.SplitBy(SplitOpts{MaxEntriesPerFile: opts.MaxEntriesPerFile})
+		if err != nil {
+			return nil, err
+		}
+		merged = append(merged, split...)
+	}
+	if len(merged) > 1 {
+		if err := AssignFileIDModifiers(merged); err != nil {
+			return nil, err
+		}
+	}
+	return merged, nil
+}
@@ -0,0 +1,113 @@
+// Copyright 2017 The ACH Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package ach
+
+import (
+	"testing"
+	"time"
+)
+
+func TestMergeAndVerify(t *testing.T) {
+	a := mockFilePPD()
+	b := mockFilePPD()
+	b.AddBatch(mockBatchPPD())
+	if err := b.Create(); err != nil {
+		t.Fatalf("%T: %s", err, err)
+	}
+	merged, err := MergeAndVerify(a, b)
+	if err != nil {
+		t.Fatalf("%T: %s", err, err)
+	}
+	if err := merged.Validate(); err != nil {
+		t.Errorf("%T: %s", err, err)
+	}
+	if len(merged.Batches) != 3 || merged.Control.BatchCount != 3 {
+		t.Errorf("MergeAndVerify expected 3 batches got: %d", len(merged.Batches))
+	}
+	if b.Batches[0].GetHeader().BatchNumber != 1 {
+		t.Error("MergeAndVerify changed the batches of b")
+	}
+
+	// a stale file control does not reconcile with the merged totals
+	b.Control.TotalCreditEntryDollarAmountInFile = 1
+	_, err = MergeAndVerify(a, b)
+	if e, ok := err.(*FileError); ok {
+		if e.FieldName != "TotalCreditEntryDollarAmountInFile" {
+			t.Errorf("%T: %s", err, err)
+		}
+	} else {
+		t.Errorf("expected FileError got: %v", err)
+	}
+
+	b = mockFilePPD()
+	b.Header.ImmediateOrigin = 231380104
+	_, err = MergeAndVerify(a, b)
+	if e, ok := err.(*FileError); ok {
+		if e.FieldName != "ImmediateOrigin" {
+			t.Errorf("%T: %s", err, err)
+		}
+	} else {
+		t.Errorf("expected FileError got: %v", err)
+	}
+}
+
+func TestMergeFilesWith(t *testing.T) {
+	a := mockFilePPD()
+	b := mockFilePPD()
+	batch := mockBatchPPD()
+	batch.GetHeader().ODFIIdentification = 23138010
+	batch.GetEntries()[0].TraceNumber = 0
+	if err := batch.Create(); err != nil {
+		t.Fatalf("%T: %s", err, err)
+	}
+	b.AddBatch(batch)
+	if err := b.Create(); err != nil {
+		t.Fatalf("%T: %s", err, err)
+	}
+	c := mockFilePPD()
+	c.Batches[0].GetHeader().EffectiveEntryDate = time.Date(2018, time.January, 2, 0, 0, 0, 0, time.UTC)
+	files := []*File{a, b, c}
+
+	merged, err := MergeFilesWith(files, MergeOpts{})
+	if err != nil {
+		t.Fatalf("%T: %s", err, err)
+	}
+	if len(merged) != 1 || len(merged[0].Batches) != 4 {
+		t.Fatalf("MergeFilesWith expected 1 file with 4 batches got: %d", len(merged))
+	}
+
+	merged, err = MergeFilesWith(files, MergeOpts{GroupByODFI: true, GroupByEffectiveDate: true})
+	if err != nil {
+		t.Fatalf("%T: %s", err, err)
+	}
+	if len(merged) != 3 {
+		t.Fatalf("MergeFilesWith expected 3 files got: %d", len(merged))
+	}
+	for i, want := range []int{2, 1, 1} {
+		if len(merged[i].Batches) != want {
+			t.Errorf("file %d expected %d batches got: %d", i, want, len(merged[i].Batches))
+		}
+		if err := merged[i].Validate(); err != nil {
+			t.Errorf("%T: %s", err, err)
+		}
+		if merged[i].Header.FileIDModifier != fileIDModifiers[i:i+1] {
+			t.Errorf("file %d FileIDModifier %q", i, merged[i].Header.FileIDModifier)
+		}
+	}
+	if merged[0].Batches[1].GetHeader().BatchNumber != 2 || merged[0].Control.BatchCount != 2 {
+		t.Error("MergeFilesWith did not renumber the batches")
+	}
+	if b.Batches[1].GetHeader().BatchNumber != 2 {
+		t.Error("MergeFilesWith changed the batches of b")
+	}
+
+	merged, err = MergeFilesWith(files, MergeOpts{GroupByODFI: true, MaxEntriesPerFile: 2})
+	if err != nil {
+		t.Fatalf("%T: %s", err, err)
+	}
+	if len(merged) != 3 || len(merged[0].Batches) != 2 || len(merged[1].Batches) != 1 {
+		t.Errorf("MergeFilesWith expected 3 files got: %d", len(merged))
+	}
+}
@@ -0,0 +1,75 @@
+// Copyright 2017 The ACH Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package ach
+
+import "fmt"
+
+// Offset is the account of the originator used to balance a batch with WithOffset.
+type Offset struct {
+	// RoutingNumber is the 9 digit ABA routing number including the check digit.
+	RoutingNumber string `json:"routing_number"`
+	// AccountNumber is the DFI account number.
+	AccountNumber string `json:"account_number"`
+	// Savings is set when the account is a savings account rather than checking.
+	Savings bool `json:"savings,omitempty"`
+	// Description is the name of the offset entry. For example "OFFSET"
+	Description string `json:"description"`
+}
+
+// WithOffset appends an entry to the offset account for the net amount of the batch so the
+// total debits equal the total credits, and rebuilds the batch control. The offset is a debit
+// when the batch has more credits than debits and a credit when it has more debits. The
+// ServiceClassCode is changed to mixed debits and credits (200). A balanced batch is not changed.
+// Only PPD, CCD and WEB batches can be offset.
+func (batch *batch) WithOffset(offset *Offset) error {
+	switch sec := batch.header.StandardEntryClassCode; sec {
+	case ppd, ccd, web:
+	default:
+		msg := fmt.Sprintf(msgBatchOffsetSEC, sec)
+		return &BatchError{BatchNumber: batch.header.BatchNumber, FieldName: "StandardEntryClassCode", Msg: msg}
+	}
+	credit, debit := batch.calculateBatchAmounts()
+	if credit == debit {
+		return nil
+	}
+	entry := NewEntryDetail(EntryParam{
+		ReceivingDFI:    offset.RoutingNumber,
+		RDFIAccount:     offset.AccountNumber,
+		IndividualName:  offset.Description,
+		TransactionCode: offsetTransactionCode(offset, credit > debit)})
+	entry.Amount = credit - debit
+	if debit > credit {
+		entry.Amount = debit - credit
+	}
+	// the offset trace number follows every entry whether or not the entry has been built
+	seq := len(batch.entries)
+	for _, e := range batch.entries {
+		if e.TraceNumberField()[:8] == batch.header.ODFIIdentificationField() && e.TraceNumber%10000000 > seq {
+			seq = e.TraceNumber % 10000000
+		}
+	}
+	entry.setTraceNumber(batch.header.ODFIIdentification, seq+1)
+	if err := entry.Validate(); err != nil {
+		return err
+	}
+	batch.header.ServiceClassCode = 200
+	batch.AddEntry(entry)
+	return batch.build()
+}
+
+// offsetTransactionCode returns the checking or savings debit transaction code when debit is set
+// and the credit transaction code otherwise.
+func offsetTransactionCode(offset *Offset, debit bool) string {
+	switch {
+	case offset.Savings && debit:
+		return "37"
+	case offset.Savings:
+		return "32"
+	case debit:
+		return "27"
+	default:
+		return "22"
+	}
+}
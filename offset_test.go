@@ -0,0 +1,88 @@
+// Copyright 2017 The ACH Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package ach
+
+import "testing"
+
+func mockOffset() *Offset {
+	return &Offset{
+		RoutingNumber: "231380104",
+		AccountNumber: "744-5678-99",
+		Description:   "OFFSET",
+	}
+}
+
+func TestBatchWithOffset(t *testing.T) {
+	file := mockFilePPD()
+	debits := mockBatchPPD()
+	debits.GetHeader().ServiceClassCode = 225
+	debits.GetEntries()[0].TransactionCode = 27
+	debits.GetEntries()[0].Amount = 2500
+	if err := debits.Create(); err != nil {
+		t.Fatalf("%T: %s", err, err)
+	}
+	file.AddBatch(debits)
+
+	for _, batch := range file.Batches {
+		if err := batch.WithOffset(mockOffset()); err != nil {
+			t.Fatalf("%T: %s", err, err)
+		}
+		if err := batch.Validate(); err != nil {
+			t.Errorf("%T: %s", err, err)
+		}
+		bc := batch.GetControl()
+		if bc.TotalCreditEntryDollarAmount != bc.TotalDebitEntryDollarAmount || bc.EntryAddendaCount != 2 {
+			t.Errorf("batch is not balanced: %v", bc.String())
+		}
+		if batch.GetHeader().ServiceClassCode != 200 {
+			t.Errorf("ServiceClassCode Expected 200 got: %v", batch.GetHeader().ServiceClassCode)
+		}
+	}
+	if code := file.Batches[0].GetEntries()[1].TransactionCode; code != 27 {
+		t.Errorf("credit batch offset TransactionCode Expected 27 got: %v", code)
+	}
+	if code := file.Batches[1].GetEntries()[1].TransactionCode; code != 22 {
+		t.Errorf("debit batch offset TransactionCode Expected 22 got: %v", code)
+	}
+	if err := file.Create(); err != nil {
+		t.Fatalf("%T: %s", err, err)
+	}
+	if err := file.Validate(); err != nil {
+		t.Errorf("%T: %s", err, err)
+	}
+
+	// a balanced batch is not changed
+	if err := file.Batches[0].WithOffset(mockOffset()); err != nil {
+		t.Errorf("%T: %s", err, err)
+	}
+	if len(file.Batches[0].GetEntries()) != 2 {
+		t.Errorf("WithOffset added an entry to a balanced batch")
+	}
+
+	offset := mockOffset()
+	offset.RoutingNumber = "231380105"
+	batch := mockBatchPPD()
+	if err := batch.WithOffset(offset); err == nil {
+		t.Error("expected error with an invalid offset routing number")
+	}
+}
+
+func TestBatchWithOffsetSEC(t *testing.T) {
+	mockBatch := mockBatchCOR()
+	if err := mockBatch.WithOffset(mockOffset()); err != nil {
+		if e, ok := err.(*BatchError); ok {
+			if e.FieldName != "StandardEntryClassCode" {
+				t.Errorf("%T: %s", err, err)
+			}
+		} else {
+			t.Errorf("%T: %s", err, err)
+		}
+	} else {
+		t.Error("expected StandardEntryClassCode error")
+	}
+	if len(mockBatch.GetEntries()) != 1 {
+		t.Errorf("offset entry was added to the COR batch")
+	}
+}
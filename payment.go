@@ -0,0 +1,189 @@
+// Copyright 2017 The ACH Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package ach
+
+import (
+	"fmt"
+	"time"
+)
+
+// Errors specific to building entries
+var (
+	msgRoutingNumberLength = "is not a %d digit routing number"
+)
+
+// PaymentAccount is a bank account identified by its routing and account number.
+type PaymentAccount struct {
+	// RoutingNumber is the 9 digit ABA routing number including the check digit.
+	RoutingNumber string `json:"routing_number"`
+	// AccountNumber is the DFI account number.
+	AccountNumber string `json:"account_number"`
+}
+
+// Payment is a single transfer between an originator and receiver. It is the input to
+// BuildFile which groups payments into batches and builds the entries for each payment.
+type Payment struct {
+	// From is the originator account. The first 8 digits of the routing number are
+	// used as the batch ODFIIdentification.
+	From PaymentAccount `json:"from"`
+	// To is the receiver account that is credited, or debited if Debit is set.
+	To PaymentAccount `json:"to"`
+	// AmountCents is the amount of the payment in cents.
+	AmountCents int `json:"amount_cents"`
+	// Debit withdraws AmountCents from the To account instead of depositing it.
+	Debit bool `json:"debit,omitempty"`
+	// Savings is set when the To account is a savings account rather than checking.
+	Savings bool `json:"savings,omitempty"`
+	// Name is the receiver's name, usually the name on the bank account.
+	Name string `json:"name"`
+	// IdentificationNumber is an optional identifier of the payment for the originator.
+	IdentificationNumber string `json:"identification_number,omitempty"`
+	// SEC is the Standard Entry Class code of the batch. For example "PPD"
+	SEC string `json:"sec"`
+	// CompanyName is the originator name displayed to the receiver.
+	CompanyName string `json:"company_name"`
+	// CompanyIdentification is the originator company identification. Frequently the federal tax ID
+	CompanyIdentification string `json:"company_identification"`
+	// CompanyEntryDescription describes the payment. For example "PAYROLL"
+	CompanyEntryDescription string `json:"company_entry_description"`
+	// EffectiveEntryDate is the date the payment is to settle.
+	EffectiveEntryDate time.Time `json:"effective_entry_date"`
+}
+
+// BuildAccountVerificationEntry returns a zero amount prenotification credit entry (23) to the
+// checking account at routing used to verify the account before live entries are sent. The
+// routing number must be the 9 digit ABA routing number including the check digit.
+func BuildAccountVerificationEntry(routing, account, name string) (*EntryDetail, error) {
+	entry := NewEntryDetail()
+	if len(routing) != 9 || entry.isNumeric(routing) != nil {
+		msg := fmt.Sprintf(msgRoutingNumberLength, 9)
+		return nil, &FieldError{FieldName: "RDFIIdentification", Value: routing, Msg: msg}
+	}
+	entry = NewEntryDetail(EntryParam{
+		ReceivingDFI:    routing,
+		RDFIAccount:     account,
+		IndividualName:  name,
+		TransactionCode: "23"})
+	if err := entry.Validate(); err != nil {
+		return nil, err
+	}
+	return entry, nil
+}
+
+// paymentBatchKey holds the batch header fields payments are grouped by.
+type paymentBatchKey struct {
+	sec                     string
+	effectiveEntryDate      string
+	odfi                    string
+	companyName             string
+	companyIdentification   string
+	companyEntryDescription string
+}
+
+// BuildFile groups payments into batches by SEC code, effective entry date, ODFI and company,
+// builds an entry for each payment and returns a created File with the header fh. Batches are
+// ordered by the first payment in each batch.
+func BuildFile(fh *FileHeader, payments []Payment) (*File, error) {
+	file := NewFile().SetHeader(*fh)
+
+	var keys []paymentBatchKey
+	grouped := make(map[paymentBatchKey][]Payment)
+	for _, p := range payments {
+		key := paymentBatchKey{
+			sec:                     p.SEC,
+			effectiveEntryDate:      file.formatSimpleDate(p.EffectiveEntryDate),
+			odfi:                    p.From.RoutingNumber,
+			companyName:             p.CompanyName,
+			companyIdentification:   p.CompanyIdentification,
+			companyEntryDescription: p.CompanyEntryDescription,
+		}
+		if _, ok := grouped[key]; !ok {
+			keys = append(keys, key)
+		}
+		grouped[key] = append(grouped[key], p)
+	}
+
+	for _, key := range keys {
+		batch, err := buildPaymentBatch(grouped[key])
+		if err != nil {
+			return nil, err
+		}
+		file.AddBatch(batch)
+	}
+	if err := file.Create(); err != nil {
+		return nil, err
+	}
+	return file, nil
+}
+
+// buildPaymentBatch creates a batch for payments sharing the same batch header fields.
+func buildPaymentBatch(payments []Payment) (Batcher, error) {
+	first := payments[0]
+	odfi := first.From.RoutingNumber
+	if len(odfi) > 8 {
+		odfi = odfi[:8]
+	}
+	batch, err := NewBatch(BatchParam{
+		ServiceClassCode:        paymentServiceClassCode(payments),
+		CompanyName:             first.CompanyName,
+		CompanyIdentification:   first.CompanyIdentification,
+		StandardEntryClass:      first.SEC,
+		CompanyEntryDescription: first.CompanyEntryDescription,
+		ODFIIdentification:      odfi})
+	if err != nil {
+		return nil, err
+	}
+	batch.GetHeader().EffectiveEntryDate = first.EffectiveEntryDate
+
+	for i, p := range payments {
+		entry := NewEntryDetail(EntryParam{
+			ReceivingDFI:    p.To.RoutingNumber,
+			RDFIAccount:     p.To.AccountNumber,
+			IDNumber:        p.IdentificationNumber,
+			IndividualName:  p.Name,
+			TransactionCode: paymentTransactionCode(p)})
+		entry.Amount = p.AmountCents
+		entry.setTraceNumber(batch.GetHeader().ODFIIdentification, i+1)
+		batch.AddEntry(entry)
+	}
+	if err := batch.Create(); err != nil {
+		return nil, err
+	}
+	return batch, nil
+}
+
+// paymentServiceClassCode returns 220 for credits only, 225 for debits only and 200 for mixed payments
+func paymentServiceClassCode(payments []Payment) string {
+	credits, debits := false, false
+	for _, p := range payments {
+		if p.Debit {
+			debits = true
+		} else {
+			credits = true
+		}
+	}
+	switch {
+	case credits && debits:
+		return "200"
+	case debits:
+		return "225"
+	default:
+		return "220"
+	}
+}
+
+// paymentTransactionCode returns the checking or savings credit or debit transaction code for p
+func paymentTransactionCode(p Payment) string {
+	switch {
+	case p.Savings && p.Debit:
+		return "37"
+	case p.Savings:
+		return "32"
+	case p.Debit:
+		return "27"
+	default:
+		return "22"
+	}
+}
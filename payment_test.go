@@ -0,0 +1,108 @@
+// Copyright 2017 The ACH Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package ach
+
+import (
+	"testing"
+	"time"
+)
+
+func mockPayment() Payment {
+	return Payment{
+		From:                    PaymentAccount{RoutingNumber: "231380104", AccountNumber: "123456"},
+		To:                      PaymentAccount{RoutingNumber: "091012984", AccountNumber: "123456789"},
+		AmountCents:             100000,
+		Name:                    "Wade Arnold",
+		SEC:                     "PPD",
+		CompanyName:             "ACME Corporation",
+		CompanyIdentification:   "123456789",
+		CompanyEntryDescription: "PAYROLL",
+		EffectiveEntryDate:      time.Now(),
+	}
+}
+
+func TestBuildFile(t *testing.T) {
+	fh := mockFileHeader()
+	p1 := mockPayment()
+	p2 := mockPayment()
+	p2.Debit = true
+	p2.Savings = true
+	p3 := mockPayment()
+	p3.SEC = "CCD"
+	p3.Name = "Best Co"
+	p4 := mockPayment()
+	p4.EffectiveEntryDate = p4.EffectiveEntryDate.AddDate(0, 0, 1)
+
+	file, err := BuildFile(&fh, []Payment{p1, p2, p3, p4})
+	if err != nil {
+		t.Fatalf("%T: %s", err, err)
+	}
+	if err := file.Validate(); err != nil {
+		t.Errorf("%T: %s", err, err)
+	}
+	if len(file.Batches) != 3 {
+		t.Fatalf("Batches Expected 3 got: %v", len(file.Batches))
+	}
+	batch := file.Batches[0]
+	if batch.GetHeader().ServiceClassCode != 200 {
+		t.Errorf("ServiceClassCode Expected 200 got: %v", batch.GetHeader().ServiceClassCode)
+	}
+	if batch.GetHeader().ODFIIdentificationField() != "23138010" {
+		t.Errorf("ODFIIdentification Expected '23138010' got: %v", batch.GetHeader().ODFIIdentificationField())
+	}
+	if len(batch.GetEntries()) != 2 {
+		t.Fatalf("Entries Expected 2 got: %v", len(batch.GetEntries()))
+	}
+	if batch.GetEntries()[1].TransactionCode != 37 {
+		t.Errorf("TransactionCode Expected 37 got: %v", batch.GetEntries()[1].TransactionCode)
+	}
+	if batch.GetControl().TotalDebitEntryDollarAmount != 100000 {
+		t.Errorf("TotalDebitEntryDollarAmount Expected 100000 got: %v", batch.GetControl().TotalDebitEntryDollarAmount)
+	}
+	if file.Batches[1].GetHeader().StandardEntryClassCode != "CCD" {
+		t.Errorf("StandardEntryClassCode Expected 'CCD' got: %v", file.Batches[1].GetHeader().StandardEntryClassCode)
+	}
+}
+
+func TestBuildFileSEC(t *testing.T) {
+	fh := mockFileHeader()
+	p := mockPayment()
+	p.SEC = "XYZ"
+	if _, err := BuildFile(&fh, []Payment{p}); err != nil {
+		if e, ok := err.(*FileError); ok {
+			if e.FieldName != "StandardEntryClassCode" {
+				t.Errorf("%T: %s", err, err)
+			}
+		} else {
+			t.Errorf("%T: %s", err, err)
+		}
+	} else {
+		t.Error("expected error for unsupported SEC code")
+	}
+}
+
+func TestBuildAccountVerificationEntry(t *testing.T) {
+	entry, err := BuildAccountVerificationEntry("091012984", "81967038518", "Jane Smith")
+	if err != nil {
+		t.Fatalf("%T: %s", err, err)
+	}
+	if entry.TransactionCode != 23 || entry.Amount != 0 || entry.HasAddenda() {
+		t.Errorf("unexpected verification entry: %v", entry.String())
+	}
+	if entry.RDFIIdentificationField() != "09101298" || entry.CheckDigit != 4 || entry.DFIAccountNumber != "81967038518" {
+		t.Errorf("unexpected verification entry account: %v", entry.String())
+	}
+
+	for _, routing := range []string{"91012984", "09101298A", "091012985"} {
+		_, err := BuildAccountVerificationEntry(routing, "81967038518", "Jane Smith")
+		if e, ok := err.(*FieldError); ok {
+			if e.FieldName != "RDFIIdentification" {
+				t.Errorf("%T: %s", err, err)
+			}
+		} else {
+			t.Errorf("%s expected FieldError got: %v", routing, err)
+		}
+	}
+}
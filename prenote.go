@@ -0,0 +1,44 @@
+// Copyright 2017 The ACH Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package ach
+
+import (
+	"fmt"
+	"time"
+)
+
+// PrenoteBankingDays is the minimum number of banking days between the settlement date of a
+// prenotification and the settlement date of the first live entry to the same account.
+const PrenoteBankingDays = 3
+
+var (
+	msgPrenoteTiming = "is %d banking days after prenote settlement date %v and requires %d"
+)
+
+// ValidatePrenoteTiming returns an error if liveDate is less than PrenoteBankingDays banking
+// days after prenoteDate. Banking days are Monday through Friday. Federal Reserve holidays
+// are not excluded.
+func ValidatePrenoteTiming(prenoteDate, liveDate time.Time) error {
+	var c converters
+	days := bankingDaysBetween(prenoteDate, liveDate)
+	if days < PrenoteBankingDays {
+		msg := fmt.Sprintf(msgPrenoteTiming, days, c.formatSimpleDate(prenoteDate), PrenoteBankingDays)
+		return &FieldError{FieldName: "EffectiveEntryDate", Value: c.formatSimpleDate(liveDate), Msg: msg}
+	}
+	return nil
+}
+
+// bankingDaysBetween returns the number of weekdays after start up to and including end.
+func bankingDaysBetween(start, end time.Time) int {
+	start = time.Date(start.Year(), start.Month(), start.Day(), 0, 0, 0, 0, time.UTC)
+	end = time.Date(end.Year(), end.Month(), end.Day(), 0, 0, 0, 0, time.UTC)
+	days := 0
+	for d := start.AddDate(0, 0, 1); !d.After(end); d = d.AddDate(0, 0, 1) {
+		if d.Weekday() != time.Saturday && d.Weekday() != time.Sunday {
+			days++
+		}
+	}
+	return days
+}
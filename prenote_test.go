@@ -0,0 +1,37 @@
+// Copyright 2017 The ACH Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package ach
+
+import (
+	"testing"
+	"time"
+)
+
+func TestValidatePrenoteTiming(t *testing.T) {
+	// Thursday
+	prenote := time.Date(2017, time.November, 2, 0, 0, 0, 0, time.UTC)
+	// Tuesday is the third banking day after Thursday
+	live := time.Date(2017, time.November, 7, 0, 0, 0, 0, time.UTC)
+	if err := ValidatePrenoteTiming(prenote, live); err != nil {
+		t.Errorf("%T: %s", err, err)
+	}
+	// Monday is only the second banking day
+	live = time.Date(2017, time.November, 6, 0, 0, 0, 0, time.UTC)
+	if err := ValidatePrenoteTiming(prenote, live); err != nil {
+		if e, ok := err.(*FieldError); ok {
+			if e.FieldName != "EffectiveEntryDate" {
+				t.Errorf("%T: %s", err, err)
+			}
+		} else {
+			t.Errorf("%T: %s", err, err)
+		}
+	} else {
+		t.Error("expected error for live entry 2 banking days after prenote")
+	}
+	// live before prenote
+	if err := ValidatePrenoteTiming(live, prenote); err == nil {
+		t.Error("expected error for live entry before prenote")
+	}
+}
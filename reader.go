@@ -6,9 +6,11 @@ package ach
 
 import (
 	"bufio"
+	"context"
 	"fmt"
 	"io"
 	"strconv"
+	"strings"
 )
 
 // ParseError is returned for parsing reader errors.
@@ -17,6 +19,11 @@ type ParseError struct {
 	Line   int    // Line number where the error accurd
 	Record string // Name of the record type being parsed
 	Err    error  // The actual error
+	// batchIndex is one more than the index in File.Batches the batch being parsed is added at,
+	// which does not count batches skipped by CollectErrors, and entryIndex is one more than the
+	// index of the entry being parsed. They are zero when no batch or entry is being parsed.
+	batchIndex int
+	entryIndex int
 }
 
 func (e *ParseError) Error() string {
@@ -26,6 +33,26 @@ func (e *ParseError) Error() string {
 	return fmt.Sprintf("line:%d record:%s %T %s", e.Line, e.Record, e.Err, e.Err)
 }
 
+// MultiError is returned by Read when the Reader is created with CollectErrors. It holds the
+// ParseError of each batch that was skipped in the order they were found.
+type MultiError []error
+
+func (e MultiError) Error() string {
+	msgs := make([]string, len(e))
+	for i, err := range e {
+		msgs[i] = err.Error()
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// RawRecord is a record as it was read and the line number it was read from. The LineNum of a
+// record in a file written as one line is the number of the record in the line. It is kept by
+// the PreserveRawRecords reader option.
+type RawRecord struct {
+	Line    string
+	LineNum int
+}
+
 // Reader reads records from a ACH-encoded file.
 type Reader struct {
 	// r handles the IO.Reader sent to be parser.
@@ -40,15 +67,161 @@ type Reader struct {
 	lineNum int
 	// recordName holds the current record name being parsed.
 	recordName string
+	// ignoreTrailingLines stops parsing after the File Control and block padding
+	ignoreTrailingLines bool
+	// trailingLines holds the lines found after the File Control and block padding
+	trailingLines []string
+	// entryDetailMapper is called with each parsed EntryDetail before it is validated
+	entryDetailMapper func(*EntryDetail)
+	// recordLength is the length of each line. Lines are converted to RecordLength before parsing
+	recordLength int
+	// skipBlankLines ignores lines that are empty or only contain spaces
+	skipBlankLines bool
+	// batchHandler is called with each batch instead of adding the batch to File
+	batchHandler func(Batcher) error
+	// streamed accumulates the control totals of the batches sent to batchHandler
+	streamed FileControl
+	// adv is set once an ADV batch is read so the File Control is parsed as an ADV File Control
+	adv bool
+	// collectErrors skips batches with errors instead of stopping
+	collectErrors bool
+	// errors holds the errors of the batches skipped with collectErrors
+	errors MultiError
+	// skipBatch ignores records until the end of a batch with an error
+	skipBatch bool
+	// preserveReserved keeps the reserved positions of the File Control as read
+	preserveReserved bool
+	// ctx is checked every contextCheckLines lines or records by ReadContext
+	ctx context.Context
+	// blockingFactor checks the File Control BlockCount and block padding when it is set
+	blockingFactor int
+	// records is the number of records parsed including block padding
+	records int
+	// preserveRawRecords keeps the line of each batch header, entry and addenda as it was read
+	preserveRawRecords bool
+	// rawLine is the current record as it was read before it was converted to RecordLength
+	rawLine string
+	// rawLineNum is the line number of rawLine, or its record number in a file written as one line
+	rawLineNum int
+	// allowZeroAmountPrenotes sets SetAllowZeroAmountPrenotes on every batch read
+	allowZeroAmountPrenotes bool
+}
+
+// contextCheckLines is the number of lines, or records of a single line file, ReadContext reads
+// between checks of its context
+const contextCheckLines = 1000
+
+// maxLineLength is the longest line a Reader can read, which allows for files written as one
+// line with LineEndingNone
+const maxLineLength = 256 * 1024 * 1024
+
+// ReaderOption configures optional Reader behavior and is passed to NewReader.
+type ReaderOption func(*Reader)
+
+// IgnoreTrailingLines stops parsing once the File Control record and its block padding
+// have been read. Any lines that follow, such as a partner checksum or footer, are not
+// parsed and are available from TrailingLines.
+func IgnoreTrailingLines() ReaderOption {
+	return func(r *Reader) {
+		r.ignoreTrailingLines = true
+	}
+}
+
+// EntryDetailMapper calls fn with each EntryDetail after it is parsed and before it is
+// validated and added to the batch. fn can normalize fields of every entry as the file is read.
+func EntryDetailMapper(fn func(*EntryDetail)) ReaderOption {
+	return func(r *Reader) {
+		r.entryDetailMapper = fn
+	}
+}
+
+// ReaderRecordLength sets the expected length of each record for partner formats that are not
+// 94 characters. Characters after the first 94 of a longer record are ignored and shorter
+// records are space padded to 94 characters before they are parsed. A length that is not
+// positive is ignored.
+func ReaderRecordLength(n int) ReaderOption {
+	return func(r *Reader) {
+		if n > 0 {
+			r.recordLength = n
+		}
+	}
+}
+
+// SkipBlankLines ignores lines anywhere in the file that are empty or only contain whitespace
+// instead of returning a record length error.
+func SkipBlankLines() ReaderOption {
+	return func(r *Reader) {
+		r.skipBlankLines = true
+	}
+}
+
+// CollectErrors skips a batch when one of its records has an error and continues reading the
+// file. Read returns the file with the batches that were read without errors and a MultiError
+// holding the ParseError of each skipped batch. Errors outside of a batch still stop reading.
+func CollectErrors() ReaderOption {
+	return func(r *Reader) {
+		r.collectErrors = true
+	}
+}
+
+// ReaderBlockingFactor checks that the File Control BlockCount and the block padding records
+// are for blocks of n records. Files are padded to blocks of BlockingFactor records but some
+// partners use a different blocking factor. By default the BlockCount and padding are not checked.
+func ReaderBlockingFactor(n int) ReaderOption {
+	return func(r *Reader) {
+		r.blockingFactor = n
+	}
+}
+
+// PreserveReserved keeps the reserved positions 56-94 of the File Control as they were read so
+// a relayed file is written with the same File Control. By default the reserved positions are
+// read and written as blanks.
+func PreserveReserved() ReaderOption {
+	return func(r *Reader) {
+		r.preserveReserved = true
+	}
+}
+
+// PreserveRawRecords keeps the line of each Batch Header, Entry Detail and addenda record as it
+// was read in the Raw field of the record so it can be compared to the record as it is written.
+// By default Raw is nil.
+func PreserveRawRecords() ReaderOption {
+	return func(r *Reader) {
+		r.preserveRawRecords = true
+	}
+}
+
+// AllowZeroAmountPrenotes validates every batch as it is read with SetAllowZeroAmountPrenotes so
+// prenote entries with a zero Amount are accepted in any batch. By default a zero amount prenote
+// is only accepted in a batch with an OriginatorStatusCode of 2.
+func AllowZeroAmountPrenotes() ReaderOption {
+	return func(r *Reader) {
+		r.allowZeroAmountPrenotes = true
+	}
 }
 
 // error creates a new ParseError based on err.
 func (r *Reader) error(err error) error {
-	return &ParseError{
+	e := &ParseError{
 		Line:   r.lineNum,
 		Record: r.recordName,
 		Err:    err,
 	}
+	if r.currentBatch != nil || r.recordName == "BatchHeader" {
+		e.batchIndex = len(r.File.Batches) + 1
+	}
+	if r.currentBatch != nil {
+		switch r.recordName {
+		case "EntryDetail", "ADVEntryDetail":
+			e.entryIndex = len(r.currentBatch.GetEntries()) + 1
+			if batch, ok := r.currentBatch.(*BatchADV); ok {
+				e.entryIndex = len(batch.GetADVEntries()) + 1
+			}
+		case "Addenda":
+			e.entryIndex = len(r.currentBatch.GetEntries())
+		}
+	}
+	return e
 }
 
 // addCurrentBatch creates the current batch type for the file being read. A successful
@@ -58,10 +231,64 @@ func (r *Reader) addCurrentBatch(batch Batcher) {
 }
 
 // NewReader returns a new ACH Reader that reads from r.
-func NewReader(r io.Reader) *Reader {
-	return &Reader{
-		scanner: bufio.NewScanner(r),
+func NewReader(r io.Reader, opts ...ReaderOption) *Reader {
+	reader := &Reader{
+		scanner:      bufio.NewScanner(r),
+		recordLength: RecordLength,
+	}
+	reader.scanner.Buffer(make([]byte, 0, bufio.MaxScanTokenSize), maxLineLength)
+	for _, opt := range opts {
+		opt(reader)
+	}
+	return reader
+}
+
+// ReadFileHeader parses and validates only the File Header record at the start of r. Only the
+// first record is read from r. If r is an io.Seeker it is returned to its starting offset, also
+// when an error is returned, so the complete file can then be parsed with NewReader. Any other
+// reader is consumed by the record read and the file can not be parsed from it again.
+func ReadFileHeader(r io.Reader) (fh *FileHeader, err error) {
+	if seeker, ok := r.(io.Seeker); ok {
+		start, seekErr := seeker.Seek(0, io.SeekCurrent)
+		if seekErr != nil {
+			return nil, seekErr
+		}
+		defer func() {
+			if _, seekErr := seeker.Seek(start, io.SeekStart); seekErr != nil && err == nil {
+				fh, err = nil, seekErr
+			}
+		}()
+	}
+	return readFileHeader(r)
+}
+
+// readFileHeader reads, parses and validates the File Header record at the start of r
+func readFileHeader(r io.Reader) (*FileHeader, error) {
+	record := make([]byte, RecordLength)
+	n, err := io.ReadFull(r, record)
+	if err != nil && err != io.ErrUnexpectedEOF {
+		return nil, err
+	}
+	reader := &Reader{lineNum: 1, recordName: "FileHeader"}
+	if n != RecordLength {
+		msg := fmt.Sprintf(msgRecordLength, n)
+		return nil, reader.error(&FileError{FieldName: "RecordLength", Value: strconv.Itoa(n), Msg: msg})
+	}
+	if string(record[:1]) != fileHeaderPos {
+		return nil, reader.error(&FileError{Msg: msgFileHeader})
+	}
+	fh := NewFileHeader()
+	fh.Parse(string(record))
+	if err := fh.Validate(); err != nil {
+		return nil, reader.error(err)
 	}
+	return &fh, nil
+}
+
+// TrailingLines returns the lines that followed the File Control and block padding
+// when the Reader was created with IgnoreTrailingLines.
+func (r *Reader) TrailingLines() []string {
+	return r.trailingLines
 }
 
 // Read reads each line of the ACH file and defines which parser to use based
@@ -69,27 +296,51 @@ func NewReader(r io.Reader) *Reader {
 // the appropriate error if issues are found.
 func (r *Reader) Read() (File, error) {
 	r.lineNum = 0
+	r.errors = nil
+	r.skipBatch = false
+	r.records = 0
+	r.adv = false
 	// read through the entire file
 	for r.scanner.Scan() {
+		if err := r.contextErr(r.lineNum); err != nil {
+			return r.File, err
+		}
 		line := r.scanner.Text()
 		r.lineNum++
+		if r.skipBlankLines && strings.TrimSpace(line) == "" {
+			continue
+		}
+		if r.isTrailingLine(line) {
+			r.trailingLines = append(r.trailingLines, line)
+			continue
+		}
 		lineLength := len(line)
 		switch {
-		case r.lineNum == 1 && lineLength > RecordLength && lineLength%RecordLength == 0:
+		case r.lineNum == 1 && lineLength > r.recordLength && lineLength%r.recordLength == 0:
 			if err := r.processFixedWidthFile(&line); err != nil {
 				return r.File, err
 			}
-		case lineLength != RecordLength:
+		case lineLength != r.recordLength:
+			// name the record being read rather than the last record parsed
+			r.recordName = recordNameOf(line)
 			msg := fmt.Sprintf(msgRecordLength, lineLength)
+			if r.recordLength != RecordLength {
+				msg = fmt.Sprintf(msgReaderRecordLength, r.recordLength, lineLength)
+			}
 			err := &FileError{FieldName: "RecordLength", Value: strconv.Itoa(lineLength), Msg: msg}
 			return r.File, r.error(err)
 		default:
-			r.line = line
-			if err := r.parseLine(); err != nil {
+			r.line = r.record(line)
+			r.rawLine = line
+			r.rawLineNum = r.lineNum
+			if err := r.parseRecord(); err != nil {
 				return r.File, err
 			}
 		}
 	}
+	if err := r.scanner.Err(); err != nil {
+		return r.File, err
+	}
 	if (FileHeader{}) == r.File.Header {
 		// Their must be at least one File Header
 		r.recordName = "FileHeader"
@@ -100,18 +351,153 @@ func (r *Reader) Read() (File, error) {
 		r.recordName = "FileControl"
 		return r.File, r.error(&FileError{Msg: msgFileControl})
 	}
-
+	if r.blockingFactor > 0 {
+		if err := r.isBlockCount(); err != nil {
+			return r.File, err
+		}
+	}
+	if len(r.errors) > 0 {
+		return r.File, r.errors
+	}
 	return r.File, nil
 }
 
+// ReadContext reads the ACH file like Read and stops with the error of ctx once ctx is canceled
+// or its deadline passes. ctx is checked before the first line and every 1000 lines after it, or
+// every 1000 records of a file written as one line.
+func (r *Reader) ReadContext(ctx context.Context) (File, error) {
+	r.ctx = ctx
+	defer func() {
+		r.ctx = nil
+	}()
+	return r.Read()
+}
+
+// contextErr returns the error of the ReadContext context when n, the number of lines or records
+// read, is a multiple of contextCheckLines
+func (r *Reader) contextErr(n int) error {
+	if r.ctx == nil || n%contextCheckLines != 0 {
+		return nil
+	}
+	return r.ctx.Err()
+}
+
+// Batches reads the ACH file one batch at a time and calls fn with each batch once it is
+// parsed and validated. Batches are not added to r.File and can be released by fn, so files
+// too large to hold in memory can be processed. The file control totals are accumulated from
+// each batch and checked against the File Control once the file has been read. Reading stops
+// and the error is returned if fn returns an error.
+func (r *Reader) Batches(fn func(Batcher) error) error {
+	r.batchHandler = fn
+	r.streamed = FileControl{}
+	defer func() {
+		r.batchHandler = nil
+	}()
+	if _, err := r.Read(); err != nil {
+		return err
+	}
+	r.recordName = "FileControl"
+	control := r.File.Control
+	if control.BatchCount != r.streamed.BatchCount {
+		msg := fmt.Sprintf(msgFileCalculatedControlEquality, r.streamed.BatchCount, control.BatchCount)
+		return r.error(&FileError{FieldName: "BatchCount", Value: strconv.Itoa(r.streamed.BatchCount), Msg: msg})
+	}
+	if control.EntryAddendaCount != r.streamed.EntryAddendaCount {
+		msg := fmt.Sprintf(msgFileCalculatedControlEquality, r.streamed.EntryAddendaCount, control.EntryAddendaCount)
+		return r.error(&FileError{FieldName: "EntryAddendaCount", Value: control.EntryAddendaCountField(), Msg: msg})
+	}
+	if control.TotalDebitEntryDollarAmountInFile != r.streamed.TotalDebitEntryDollarAmountInFile {
+		msg := fmt.Sprintf(msgFileCalculatedControlEquality, r.streamed.TotalDebitEntryDollarAmountInFile, control.TotalDebitEntryDollarAmountInFile)
+		return r.error(&FileError{FieldName: "TotalDebitEntryDollarAmountInFile", Value: control.TotalDebitEntryDollarAmountInFileField(), Msg: msg})
+	}
+	if control.TotalCreditEntryDollarAmountInFile != r.streamed.TotalCreditEntryDollarAmountInFile {
+		msg := fmt.Sprintf(msgFileCalculatedControlEquality, r.streamed.TotalCreditEntryDollarAmountInFile, control.TotalCreditEntryDollarAmountInFile)
+		return r.error(&FileError{FieldName: "TotalCreditEntryDollarAmountInFile", Value: control.TotalCreditEntryDollarAmountInFileField(), Msg: msg})
+	}
+	if r.streamed.EntryHashField() != control.EntryHashField() {
+		msg := fmt.Sprintf(msgFileCalculatedControlEquality, r.streamed.EntryHashField(), control.EntryHashField())
+		return r.error(&FileError{FieldName: "EntryHash", Value: control.EntryHashField(), Msg: msg})
+	}
+	return nil
+}
+
+// streamBatch accumulates the control totals of batch and sends it to batchHandler
+func (r *Reader) streamBatch(batch Batcher) error {
+	bc := batch.GetControl()
+	r.streamed.BatchCount++
+	r.streamed.EntryAddendaCount += bc.EntryAddendaCount
+	r.streamed.EntryHash += bc.EntryHash
+	r.streamed.TotalDebitEntryDollarAmountInFile += bc.TotalDebitEntryDollarAmount
+	r.streamed.TotalCreditEntryDollarAmountInFile += bc.TotalCreditEntryDollarAmount
+	return r.batchHandler(batch)
+}
+
+// isBlockCount checks that the records read, including block padding, fill whole blocks of
+// blockingFactor records and that the File Control BlockCount is the number of blocks.
+func (r *Reader) isBlockCount() error {
+	r.recordName = "FileControl"
+	if r.records%r.blockingFactor != 0 {
+		msg := fmt.Sprintf(msgReaderBlockPadding, r.records, r.blockingFactor)
+		return r.error(&FileError{FieldName: "BlockCount", Value: strconv.Itoa(r.records), Msg: msg})
+	}
+	if blocks := r.records / r.blockingFactor; r.File.Control.BlockCount != blocks {
+		msg := fmt.Sprintf(msgReaderBlockCount, blocks, r.blockingFactor)
+		return r.error(&FileError{FieldName: "BlockCount", Value: r.File.Control.BlockCountField(), Msg: msg})
+	}
+	return nil
+}
+
+// isTrailingLine returns true if IgnoreTrailingLines is set and line follows the File Control
+// and block padding. Once a trailing line is found all following lines are trailing.
+func (r *Reader) isTrailingLine(line string) bool {
+	if !r.ignoreTrailingLines || (FileControl{}) == r.File.Control {
+		return false
+	}
+	if len(r.trailingLines) > 0 {
+		return true
+	}
+	return line != strings.Repeat("9", r.recordLength)
+}
+
+// recordNames are the names of each record type used in a ParseError
+var recordNames = map[string]string{
+	fileHeaderPos:   "FileHeader",
+	batchHeaderPos:  "BatchHeader",
+	entryDetailPos:  "EntryDetail",
+	entryAddendaPos: "Addenda",
+	batchControlPos: "BatchControl",
+	fileControlPos:  "FileControl",
+}
+
+// recordNameOf returns the name of the record type of line or "" if it is not known
+func recordNameOf(line string) string {
+	if line == "" {
+		return ""
+	}
+	return recordNames[line[:1]]
+}
+
+// record converts a line of recordLength characters to a record of RecordLength characters
+func (r *Reader) record(line string) string {
+	if len(line) > RecordLength {
+		return line[:RecordLength]
+	}
+	return line + strings.Repeat(" ", RecordLength-len(line))
+}
+
 func (r *Reader) processFixedWidthFile(line *string) error {
 	// it should be safe to parse this byte by byte since ACH files are ascii only
 	record := ""
 	for i, c := range *line {
 		record = record + string(c)
-		if i > 0 && (i+1)%RecordLength == 0 {
-			r.line = record
-			if err := r.parseLine(); err != nil {
+		if i > 0 && (i+1)%r.recordLength == 0 {
+			if err := r.contextErr((i + 1) / r.recordLength); err != nil {
+				return err
+			}
+			r.line = r.record(record)
+			r.rawLine = record
+			r.rawLineNum = (i + 1) / r.recordLength
+			if err := r.parseRecord(); err != nil {
 				return err
 			}
 			record = ""
@@ -120,6 +506,47 @@ func (r *Reader) processFixedWidthFile(line *string) error {
 	return nil
 }
 
+// rawRecord returns the current record as it was read when PreserveRawRecords is set and nil otherwise
+func (r *Reader) rawRecord() *RawRecord {
+	if !r.preserveRawRecords {
+		return nil
+	}
+	return &RawRecord{Line: r.rawLine, LineNum: r.rawLineNum}
+}
+
+// parseRecord parses r.line. With CollectErrors a ParseError in a batch is kept and the rest of
+// the batch is skipped.
+func (r *Reader) parseRecord() error {
+	r.records++
+	recordType := r.line[:1]
+	if r.skipBatch {
+		switch recordType {
+		case batchControlPos:
+			r.skipBatch = false
+			return nil
+		case batchHeaderPos, fileControlPos:
+			r.skipBatch = false
+		default:
+			return nil
+		}
+	}
+	err := r.parseLine()
+	if err == nil || !r.collectErrors {
+		return err
+	}
+	if _, ok := err.(*ParseError); !ok {
+		return err
+	}
+	switch recordType {
+	case batchHeaderPos, entryDetailPos, entryAddendaPos, batchControlPos:
+		r.errors = append(r.errors, err)
+		r.currentBatch = nil
+		r.skipBatch = recordType != batchControlPos
+		return nil
+	}
+	return err
+}
+
 func (r *Reader) parseLine() error {
 	switch r.line[:1] {
 	case fileHeaderPos:
@@ -146,7 +573,13 @@ func (r *Reader) parseLine() error {
 			r.recordName = "Batches"
 			return r.error(err)
 		}
-		r.File.AddBatch(r.currentBatch)
+		if r.batchHandler != nil {
+			if err := r.streamBatch(r.currentBatch); err != nil {
+				return err
+			}
+		} else {
+			r.File.AddBatch(r.currentBatch)
+		}
 		r.currentBatch = nil
 	case fileControlPos:
 		if r.line[:2] == "99" {
@@ -189,6 +622,7 @@ func (r *Reader) parseBatchHeader() error {
 	// Ensure we have a valid batch header before building a batch.
 	bh := NewBatchHeader()
 	bh.Parse(r.line)
+	bh.Raw = r.rawRecord()
 	if err := bh.Validate(); err != nil {
 		return r.error(err)
 	}
@@ -201,6 +635,14 @@ func (r *Reader) parseBatchHeader() error {
 	}
 
 	batch.SetHeader(bh)
+	if _, ok := batch.(*BatchADV); ok {
+		r.adv = true
+	}
+	if r.allowZeroAmountPrenotes {
+		if b, ok := batch.(batchOptioner); ok {
+			b.options().allowZeroAmountPrenotes = true
+		}
+	}
 	r.addCurrentBatch(batch)
 	return nil
 }
@@ -211,8 +653,15 @@ func (r *Reader) parseEntryDetail() error {
 	if r.currentBatch == nil {
 		return r.error(&FileError{Msg: msgFileBatchOutside})
 	}
+	if batch, ok := r.currentBatch.(*BatchADV); ok {
+		return r.parseADVEntryDetail(batch)
+	}
 	ed := new(EntryDetail)
 	ed.Parse(r.line)
+	ed.Raw = r.rawRecord()
+	if r.entryDetailMapper != nil {
+		r.entryDetailMapper(ed)
+	}
 	if err := ed.Validate(); err != nil {
 		return r.error(err)
 	}
@@ -220,6 +669,18 @@ func (r *Reader) parseEntryDetail() error {
 	return nil
 }
 
+// parseADVEntryDetail takes the input record string and parses the ADVEntryDetail values
+func (r *Reader) parseADVEntryDetail(batch *BatchADV) error {
+	r.recordName = "ADVEntryDetail"
+	ed := NewADVEntryDetail()
+	ed.Parse(r.line)
+	if err := ed.Validate(); err != nil {
+		return r.error(err)
+	}
+	batch.AddADVEntry(ed)
+	return nil
+}
+
 // parseAddendaRecord takes the input record string and parses the AddendaRecord values
 func (r *Reader) parseAddenda() error {
 	r.recordName = "Addenda"
@@ -235,10 +696,14 @@ func (r *Reader) parseAddenda() error {
 	entry := r.currentBatch.GetEntries()[entryIndex]
 
 	switch sec := r.currentBatch.GetHeader().StandardEntryClassCode; sec {
-	case ppd:
-		if entry.AddendaRecordIndicator == 1 {
+	case ppd, ctx, enr, trx, pop:
+		if entry.HasAddenda() && r.line[1:3] == "99" {
+			// dishonored and contested returns are sent in batches of the original SEC code
+			return r.parseReturnAddenda(entryIndex)
+		} else if entry.HasAddenda() {
 			addenda := Addenda{}
 			addenda.Parse(r.line)
+			addenda.Raw = r.rawRecord()
 			if err := addenda.Validate(); err != nil {
 				return r.error(err)
 			}
@@ -248,13 +713,17 @@ func (r *Reader) parseAddenda() error {
 			return r.error(&FileError{FieldName: "AddendaRecordIndicator", Msg: msg})
 		}
 	case web, ccd, cor: // only care for returns
-		if entry.AddendaRecordIndicator == 1 {
-			returnAddenda := ReturnAddenda{}
-			returnAddenda.Parse(r.line)
-			if err := returnAddenda.Validate(); err != nil {
+		if sec == cor && entry.HasAddenda() && r.line[1:3] == "98" {
+			r.recordName = "Addenda98"
+			addenda98 := NewAddenda98()
+			addenda98.Parse(r.line)
+			addenda98.Raw = r.rawRecord()
+			if err := addenda98.Validate(); err != nil {
 				return r.error(err)
 			}
-			r.currentBatch.GetEntries()[entryIndex].AddReturnAddenda(returnAddenda)
+			r.currentBatch.GetEntries()[entryIndex].Addenda98 = addenda98
+		} else if entry.HasAddenda() {
+			return r.parseReturnAddenda(entryIndex)
 		} else {
 			msg := fmt.Sprintf(msgBatchAddendaIndicator)
 			return r.error(&FileError{FieldName: "AddendaRecordIndicator", Msg: msg})
@@ -263,6 +732,19 @@ func (r *Reader) parseAddenda() error {
 	return nil
 }
 
+// parseReturnAddenda parses the ReturnAddenda of the entry at entryIndex in the current batch
+func (r *Reader) parseReturnAddenda(entryIndex int) error {
+	returnAddenda := ReturnAddenda{}
+	returnAddenda.Parse(r.line)
+	returnAddenda.Raw = r.rawRecord()
+	if err := returnAddenda.Validate(); err != nil {
+		return r.error(err)
+	}
+	r.currentBatch.GetEntries()[entryIndex].AddReturnAddenda(returnAddenda)
+	r.currentBatch.GetEntries()[entryIndex].Category = CategoryReturn
+	return nil
+}
+
 // parseBatchControl takes the input record string and parses the BatchControlRecord values
 func (r *Reader) parseBatchControl() error {
 	r.recordName = "BatchControl"
@@ -270,6 +752,16 @@ func (r *Reader) parseBatchControl() error {
 		// batch Control without a current batch
 		return r.error(&FileError{Msg: msgFileBatchOutside})
 	}
+	if batch, ok := r.currentBatch.(*BatchADV); ok {
+		r.recordName = "ADVBatchControl"
+		bc := NewADVBatchControl()
+		bc.Parse(r.line)
+		if err := bc.Validate(); err != nil {
+			return r.error(err)
+		}
+		batch.SetADVControl(bc)
+		return nil
+	}
 	r.currentBatch.GetControl().Parse(r.line)
 	if err := r.currentBatch.GetControl().Validate(); err != nil {
 		return r.error(err)
@@ -284,7 +776,20 @@ func (r *Reader) parseFileControl() error {
 		// Can be only one file control per file
 		return r.error(&FileError{Msg: msgFileControl})
 	}
+	if r.adv {
+		r.recordName = "ADVFileControl"
+		fc := NewADVFileControl()
+		fc.Parse(r.line)
+		if err := fc.Validate(); err != nil {
+			return r.error(err)
+		}
+		r.File.Control = fc.fileControl()
+		return nil
+	}
 	r.File.Control.Parse(r.line)
+	if r.preserveReserved {
+		r.File.Control.reserved = r.line[55:94]
+	}
 	if err := r.File.Control.Validate(); err != nil {
 		return r.error(err)
 	}
@@ -5,6 +5,12 @@
 package ach
 
 import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
 	"os"
 	"strings"
 	"testing"
@@ -444,3 +450,708 @@ func TestFileAddendaOutsideEntry(t *testing.T) {
 		t.Errorf("%T: %s", err, err)
 	}
 }
+
+func TestFileIgnoreTrailingLines(t *testing.T) {
+	file := mockFilePPD()
+	buf := new(bytes.Buffer)
+	w := NewWriter(buf)
+	if err := w.Write(file); err != nil {
+		t.Errorf("%T: %s", err, err)
+	}
+	w.Flush()
+	checksum := "CHECKSUM 0A1B2C3D"
+	buf.WriteString(checksum + "\n")
+
+	// without the option the footer is an invalid record
+	r := NewReader(strings.NewReader(buf.String()))
+	if _, err := r.Read(); err == nil {
+		t.Error("expected error reading trailing footer line")
+	}
+
+	r = NewReader(strings.NewReader(buf.String()), IgnoreTrailingLines())
+	if _, err := r.Read(); err != nil {
+		t.Errorf("%T: %s", err, err)
+	}
+	if err := r.File.Validate(); err != nil {
+		t.Errorf("%T: %s", err, err)
+	}
+	if len(r.TrailingLines()) != 1 || r.TrailingLines()[0] != checksum {
+		t.Errorf("TrailingLines Expected %q got: %q", checksum, r.TrailingLines())
+	}
+}
+
+func TestFileEntryDetailMapper(t *testing.T) {
+	file := mockFilePPD()
+	file.Batches[0].GetEntries()[0].DFIAccountNumber = "XX123456789"
+	buf := new(bytes.Buffer)
+	w := NewWriter(buf)
+	if err := w.Write(file); err != nil {
+		t.Errorf("%T: %s", err, err)
+	}
+	w.Flush()
+
+	mapper := func(ed *EntryDetail) {
+		ed.DFIAccountNumber = strings.TrimPrefix(ed.DFIAccountNumber, "XX")
+	}
+	r := NewReader(strings.NewReader(buf.String()), EntryDetailMapper(mapper))
+	f, err := r.Read()
+	if err != nil {
+		t.Fatalf("%T: %s", err, err)
+	}
+	if got := f.Batches[0].GetEntries()[0].DFIAccountNumber; strings.TrimSpace(got) != "123456789" {
+		t.Errorf("DFIAccountNumber Expected '123456789' got: %q", got)
+	}
+}
+
+func TestFileReaderRecordLength(t *testing.T) {
+	file := mockFilePPD()
+	buf := new(bytes.Buffer)
+	w := NewWriter(buf)
+	if err := w.Write(file); err != nil {
+		t.Errorf("%T: %s", err, err)
+	}
+	w.Flush()
+	var lines []string
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		lines = append(lines, line+"EXTRA ")
+	}
+	extended := strings.Join(lines, "\n")
+
+	if _, err := NewReader(strings.NewReader(extended)).Read(); err == nil {
+		t.Error("expected error reading 100 character records")
+	}
+	f, err := NewReader(strings.NewReader(extended), ReaderRecordLength(100)).Read()
+	if err != nil {
+		t.Fatalf("%T: %s", err, err)
+	}
+	if err := f.Validate(); err != nil {
+		t.Errorf("%T: %s", err, err)
+	}
+	if f.Batches[0].GetEntries()[0].String() != file.Batches[0].GetEntries()[0].String() {
+		t.Errorf("ReaderRecordLength entry got: %v", f.Batches[0].GetEntries()[0].String())
+	}
+
+	_, err = NewReader(strings.NewReader(buf.String()), ReaderRecordLength(100)).Read()
+	if p, ok := err.(*ParseError); ok {
+		if e, ok := p.Err.(*FileError); ok {
+			if e.Msg != fmt.Sprintf(msgReaderRecordLength, 100, 94) {
+				t.Errorf("%T: %s", e, e)
+			}
+		} else {
+			t.Errorf("%T: %s", p.Err, p.Err)
+		}
+	} else {
+		t.Errorf("expected ParseError got: %v", err)
+	}
+}
+
+// TestReaderTruncatedRecords every record of a fixture truncated to each shorter length returns
+// a ParseError naming the truncated record and its line instead of panicking
+func TestReaderTruncatedRecords(t *testing.T) {
+	fixture, err := ioutil.ReadFile("./testdata/ppd-debit.ach")
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimRight(string(fixture), "\n"), "\n")
+	for i, line := range lines {
+		for n := 1; n < RecordLength; n++ {
+			truncated := append(append([]string(nil), lines[:i]...), line[:n])
+			for _, opts := range [][]ReaderOption{nil, {ReaderRecordLength(0)}, {CollectErrors()}} {
+				_, err := NewReader(strings.NewReader(strings.Join(truncated, "\n")), opts...).Read()
+				if p, ok := err.(*ParseError); ok {
+					if p.Line != i+1 || p.Record != recordNames[line[:1]] {
+						t.Fatalf("line %d truncated to %d: %s", i+1, n, p)
+					}
+				} else {
+					t.Fatalf("line %d truncated to %d expected ParseError got: %v", i+1, n, err)
+				}
+			}
+		}
+	}
+}
+
+// TestParseShortRecords ensures the exported Parse methods pad a short record instead of panicking
+func TestParseShortRecords(t *testing.T) {
+	parsers := []interface{ Parse(string) }{
+		new(FileHeader), new(BatchHeader), new(EntryDetail), new(Addenda), new(Addenda98),
+		new(ReturnAddenda), new(BatchControl), new(FileControl), new(ADVEntryDetail),
+		new(ADVBatchControl), new(ADVFileControl),
+	}
+	for _, p := range parsers {
+		for _, record := range []string{"", "6", "627"} {
+			p.Parse(record)
+		}
+	}
+	ed := new(EntryDetail)
+	ed.Parse("62709101298")
+	if ed.TransactionCode != 27 || ed.RDFIIdentification != 9101298 || strings.TrimSpace(ed.DFIAccountNumber) != "" {
+		t.Errorf("short EntryDetail parsed as %q", ed.String())
+	}
+}
+
+func TestFileSkipBlankLines(t *testing.T) {
+	f, err := os.Open("./testdata/ppd-debit-blank-lines.ach")
+	if err != nil {
+		t.Fatalf("%T: %s", err, err)
+	}
+	defer f.Close()
+	if _, err := NewReader(f).Read(); err == nil {
+		t.Error("expected error reading blank lines")
+	}
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		t.Fatalf("%T: %s", err, err)
+	}
+	file, err := NewReader(f, SkipBlankLines()).Read()
+	if err != nil {
+		t.Fatalf("%T: %s", err, err)
+	}
+	if err := file.Validate(); err != nil {
+		t.Errorf("%T: %s", err, err)
+	}
+	if len(file.Batches) != 1 || len(file.Batches[0].GetEntries()) != 1 {
+		t.Errorf("SkipBlankLines read %d batches", len(file.Batches))
+	}
+}
+
+func TestReaderBatches(t *testing.T) {
+	file := mockFilePPD()
+	file.AddBatch(mockBatchPPD())
+	if err := file.Create(); err != nil {
+		t.Fatalf("%T: %s", err, err)
+	}
+	buf := new(bytes.Buffer)
+	w := NewWriter(buf)
+	if err := w.Write(file); err != nil {
+		t.Errorf("%T: %s", err, err)
+	}
+	w.Flush()
+
+	r := NewReader(strings.NewReader(buf.String()))
+	var batchNumbers []int
+	err := r.Batches(func(batch Batcher) error {
+		batchNumbers = append(batchNumbers, batch.GetHeader().BatchNumber)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("%T: %s", err, err)
+	}
+	if len(batchNumbers) != 2 || batchNumbers[0] != 1 || batchNumbers[1] != 2 {
+		t.Errorf("Batches got batch numbers: %v", batchNumbers)
+	}
+	if len(r.File.Batches) != 0 || r.File.Control.BatchCount != 2 {
+		t.Errorf("Batches kept %d batches in the file", len(r.File.Batches))
+	}
+
+	// the callback error stops reading
+	stop := errors.New("stop")
+	calls := 0
+	err = NewReader(strings.NewReader(buf.String())).Batches(func(batch Batcher) error {
+		calls++
+		return stop
+	})
+	if err != stop || calls != 1 {
+		t.Errorf("Batches expected stop after 1 call got: %v after %d", err, calls)
+	}
+
+	// remove the second batch so the file control totals are out of balance
+	lines := strings.Split(buf.String(), "\n")
+	missing := strings.Join(append(lines[:4:4], lines[7:]...), "\n")
+	err = NewReader(strings.NewReader(missing)).Batches(func(batch Batcher) error { return nil })
+	if p, ok := err.(*ParseError); ok {
+		if e, ok := p.Err.(*FileError); ok {
+			if e.FieldName != "BatchCount" {
+				t.Errorf("%T: %s", e, e)
+			}
+		} else {
+			t.Errorf("%T: %s", p.Err, p.Err)
+		}
+	} else {
+		t.Errorf("expected ParseError got: %v", err)
+	}
+}
+
+func TestReaderCollectErrors(t *testing.T) {
+	file := mockFilePPD()
+	file.AddBatch(mockBatchPPD())
+	file.AddBatch(mockBatchPPD())
+	if err := file.Create(); err != nil {
+		t.Fatalf("%T: %s", err, err)
+	}
+	buf := new(bytes.Buffer)
+	w := NewWriter(buf)
+	if err := w.Write(file); err != nil {
+		t.Errorf("%T: %s", err, err)
+	}
+	w.Flush()
+
+	// invalid transaction code in the entry of the second batch
+	lines := strings.Split(buf.String(), "\n")
+	lines[5] = lines[5][:1] + "99" + lines[5][3:]
+	bad := strings.Join(lines, "\n")
+
+	_, err := NewReader(strings.NewReader(bad)).Read()
+	if loc, ok := AsFieldError(err); ok {
+		if loc.FieldName != "TransactionCode" || loc.BatchIndex != 1 || loc.EntryIndex != 0 || loc.LineNumber != 6 {
+			t.Errorf("unexpected location %+v", loc)
+		}
+	} else {
+		t.Errorf("expected Read to stop at the invalid entry got: %v", err)
+	}
+
+	f, err := NewReader(strings.NewReader(bad), CollectErrors()).Read()
+	if errs, ok := err.(MultiError); ok {
+		if len(errs) != 1 {
+			t.Fatalf("expected 1 error got: %v", errs)
+		}
+		if p, ok := errs[0].(*ParseError); ok {
+			if p.Line != 6 || p.Record != "EntryDetail" {
+				t.Errorf("%T: %s", p, p)
+			}
+		} else {
+			t.Errorf("expected ParseError got: %v", errs[0])
+		}
+	} else {
+		t.Fatalf("expected MultiError got: %v", err)
+	}
+	if len(f.Batches) != 2 {
+		t.Fatalf("expected 2 batches got: %d", len(f.Batches))
+	}
+	if f.Batches[0].GetHeader().BatchNumber != 1 || f.Batches[1].GetHeader().BatchNumber != 3 {
+		t.Errorf("unexpected batches %d and %d", f.Batches[0].GetHeader().BatchNumber, f.Batches[1].GetHeader().BatchNumber)
+	}
+
+	// the batch index of an error counts only the batches added to the file
+	lines[8] = lines[8][:1] + "99" + lines[8][3:]
+	_, err = NewReader(strings.NewReader(strings.Join(lines, "\n")), CollectErrors()).Read()
+	if errs, ok := err.(MultiError); ok && len(errs) == 2 {
+		if loc, ok := AsFieldError(errs[1]); !ok || loc.BatchIndex != 1 || loc.LineNumber != 9 {
+			t.Errorf("unexpected location %+v", loc)
+		}
+	} else {
+		t.Errorf("expected 2 errors got: %v", err)
+	}
+
+	if _, err := NewReader(strings.NewReader(buf.String()), CollectErrors()).Read(); err != nil {
+		t.Errorf("%T: %s", err, err)
+	}
+}
+
+// TestFileControlRoundTrip the File Control of a known fixture is written as it was read
+func TestFileControlRoundTrip(t *testing.T) {
+	fixture, err := ioutil.ReadFile("./testdata/ppd-debit.ach")
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimRight(string(fixture), "\n"), "\n")
+	control := lines[len(lines)-1]
+
+	for _, opts := range [][]ReaderOption{nil, {PreserveReserved()}} {
+		file, err := NewReader(bytes.NewReader(fixture), opts...).Read()
+		if err != nil {
+			t.Fatalf("%T: %s", err, err)
+		}
+		buf := new(bytes.Buffer)
+		if err := NewWriter(buf).WriteAll([]*File{&file}); err != nil {
+			t.Fatalf("%T: %s", err, err)
+		}
+		written := strings.Split(buf.String(), "\n")
+		if written[len(lines)-1] != control {
+			t.Errorf("File Control Expected\n%q\ngot\n%q", control, written[len(lines)-1])
+		}
+	}
+}
+
+// TestReadBatchNumbers batch numbers starting at 100 are read as they are and kept by
+// CreateWith PreserveBatchNumbers
+func TestReadBatchNumbers(t *testing.T) {
+	fixture, err := ioutil.ReadFile("./testdata/ppd-batch-number-100.ach")
+	if err != nil {
+		t.Fatal(err)
+	}
+	file, err := NewReader(bytes.NewReader(fixture)).Read()
+	if err != nil {
+		t.Fatalf("%T: %s", err, err)
+	}
+	for _, n := range []int{100, 101} {
+		batch, ok := file.BatchByNumber(n)
+		if !ok || batch.GetControl().BatchNumber != n {
+			t.Errorf("BatchByNumber(%d) was not found", n)
+		}
+	}
+	if err := file.CreateWith(FileOpts{PreserveBatchNumbers: true}); err != nil {
+		t.Fatalf("%T: %s", err, err)
+	}
+	buf := new(bytes.Buffer)
+	if err := NewWriter(buf).WriteAll([]*File{&file}); err != nil {
+		t.Fatalf("%T: %s", err, err)
+	}
+	if buf.String() != string(fixture) {
+		t.Errorf("batch numbers were not preserved\ngot:\n%s\nwant:\n%s", buf, fixture)
+	}
+	if err := file.Create(); err != nil {
+		t.Fatalf("%T: %s", err, err)
+	}
+	if _, ok := file.BatchByNumber(1); !ok {
+		t.Error("Create did not number the batches from 1")
+	}
+}
+
+// TestReaderPreserveRawRecords records keep the line they were read from with PreserveRawRecords
+func TestReaderPreserveRawRecords(t *testing.T) {
+	file := mockFilePPD()
+	file.Batches[0].GetEntries()[0].AddAddenda(mockAddenda())
+	if err := file.Batches[0].Create(); err != nil {
+		t.Fatalf("%T: %s", err, err)
+	}
+	if err := file.Create(); err != nil {
+		t.Fatalf("%T: %s", err, err)
+	}
+	buf := new(bytes.Buffer)
+	if err := NewWriter(buf).WriteAll([]*File{file}); err != nil {
+		t.Fatalf("%T: %s", err, err)
+	}
+	lines := strings.Split(buf.String(), "\n")
+	// records read from a partner with 100 character lines keep the extra characters
+	for i := range lines {
+		if lines[i] != "" {
+			lines[i] += "EXTRA "
+		}
+	}
+	extended := strings.Join(lines, "\n")
+
+	read, err := NewReader(strings.NewReader(extended), ReaderRecordLength(100), PreserveRawRecords()).Read()
+	if err != nil {
+		t.Fatalf("%T: %s", err, err)
+	}
+	bh := read.Batches[0].GetHeader()
+	entry := read.Batches[0].GetEntries()[0]
+	if bh.Raw == nil || *bh.Raw != (RawRecord{Line: lines[1], LineNum: 2}) {
+		t.Errorf("BatchHeader Raw got: %+v", bh.Raw)
+	}
+	if entry.Raw == nil || *entry.Raw != (RawRecord{Line: lines[2], LineNum: 3}) {
+		t.Errorf("EntryDetail Raw got: %+v", entry.Raw)
+	}
+	if a := entry.Addendum[0].Raw; a == nil || *a != (RawRecord{Line: lines[3], LineNum: 4}) {
+		t.Errorf("Addenda Raw got: %+v", a)
+	}
+
+	read, err = NewReader(strings.NewReader(extended), ReaderRecordLength(100)).Read()
+	if err != nil {
+		t.Fatalf("%T: %s", err, err)
+	}
+	if read.Batches[0].GetHeader().Raw != nil || read.Batches[0].GetEntries()[0].Raw != nil {
+		t.Error("Raw records were kept without PreserveRawRecords")
+	}
+
+	// records of a file written as one line are numbered by their position in the line
+	one := strings.Join(strings.Split(buf.String(), "\n"), "")
+	read, err = NewReader(strings.NewReader(one), PreserveRawRecords()).Read()
+	if err != nil {
+		t.Fatalf("%T: %s", err, err)
+	}
+	entry = read.Batches[0].GetEntries()[0]
+	if entry.Raw == nil || *entry.Raw != (RawRecord{Line: one[2*RecordLength : 3*RecordLength], LineNum: 3}) {
+		t.Errorf("EntryDetail Raw got: %+v", entry.Raw)
+	}
+}
+
+// TestFileMixedLineEndings records terminated by CRLF and LF parse the same as a LF file
+func TestFileMixedLineEndings(t *testing.T) {
+	file := mockFilePPD()
+	buf := new(bytes.Buffer)
+	w := NewWriter(buf)
+	if err := w.Write(file); err != nil {
+		t.Errorf("%T: %s", err, err)
+	}
+	w.Flush()
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	for i := range lines {
+		if i%2 == 0 {
+			lines[i] += "\r"
+		}
+	}
+	mixed := strings.Join(lines, "\n")
+
+	want, err := NewReader(strings.NewReader(buf.String())).Read()
+	if err != nil {
+		t.Fatalf("%T: %s", err, err)
+	}
+	got, err := NewReader(strings.NewReader(mixed)).Read()
+	if err != nil {
+		t.Fatalf("%T: %s", err, err)
+	}
+	if got.Header.String() != want.Header.String() ||
+		got.Batches[0].GetHeader().String() != want.Batches[0].GetHeader().String() ||
+		got.Batches[0].GetEntries()[0].String() != want.Batches[0].GetEntries()[0].String() ||
+		got.Control.String() != want.Control.String() {
+		t.Error("CRLF records did not parse the same as LF records")
+	}
+	if got.Batches[0].GetHeader().CompanyName != want.Batches[0].GetHeader().CompanyName {
+		t.Errorf("CompanyName got: %q", got.Batches[0].GetHeader().CompanyName)
+	}
+}
+
+// TestReturnBlankEffectiveEntryDate return batches have a blank effective entry date
+func TestReturnBlankEffectiveEntryDate(t *testing.T) {
+	f, err := os.Open("./testdata/return-blank-effective-date.ach")
+	if err != nil {
+		t.Fatalf("%T: %s", err, err)
+	}
+	defer f.Close()
+	r := NewReader(f)
+	file, err := r.Read()
+	if err != nil {
+		t.Fatalf("%T: %s", err, err)
+	}
+	if err = file.Validate(); err != nil {
+		t.Errorf("%T: %s", err, err)
+	}
+	batch := file.Batches[0]
+	if !batch.GetHeader().EffectiveEntryDate.IsZero() {
+		t.Errorf("EffectiveEntryDate Expected zero got: %v", batch.GetHeader().EffectiveEntryDate)
+	}
+	if batch.GetHeader().EffectiveEntryDateField() != "      " {
+		t.Errorf("EffectiveEntryDateField Expected blank got: %q", batch.GetHeader().EffectiveEntryDateField())
+	}
+	if _, err := batch.EffectiveEntryDate(); err != nil {
+		t.Errorf("%T: %s", err, err)
+	}
+	if len(batch.GetEntries()[0].ReturnAddendum) != 1 {
+		t.Error("ReturnAddendum Expected 1 return addenda")
+	}
+	if batch.GetEntries()[0].Category != CategoryReturn {
+		t.Errorf("Category Expected %s got: %q", CategoryReturn, batch.GetEntries()[0].Category)
+	}
+}
+
+func TestReadFileHeader(t *testing.T) {
+	f, err := os.Open("./testdata/ppd-debit.ach")
+	if err != nil {
+		t.Fatalf("%T: %s", err, err)
+	}
+	defer f.Close()
+	fh, err := ReadFileHeader(f)
+	if err != nil {
+		t.Fatalf("%T: %s", err, err)
+	}
+	if fh.ImmediateDestinationField() != " 076401251" {
+		t.Errorf("ImmediateDestination Expected ' 076401251' got: %v", fh.ImmediateDestinationField())
+	}
+	// the file is returned to the start for a full parse
+	file, err := NewReader(f).Read()
+	if err != nil {
+		t.Fatalf("%T: %s", err, err)
+	}
+	if file.Header.String() != fh.String() {
+		t.Errorf("FileHeader Expected %v got: %v", fh.String(), file.Header.String())
+	}
+}
+
+func TestReadFileHeaderRecordType(t *testing.T) {
+	bh := mockBatchHeader()
+	_, err := ReadFileHeader(strings.NewReader(bh.String()))
+	if p, ok := err.(*ParseError); ok {
+		if e, ok := p.Err.(*FileError); ok {
+			if e.Msg != msgFileHeader {
+				t.Errorf("%T: %s", e, e)
+			}
+		} else {
+			t.Errorf("%T: %s", p.Err, p.Err)
+		}
+	} else {
+		t.Errorf("%T: %s", err, err)
+	}
+}
+
+// TestReadFileHeaderSeekOnError a seekable reader is returned to its start when the File Header is not valid
+func TestReadFileHeaderSeekOnError(t *testing.T) {
+	r := strings.NewReader(mockBatchHeader().String())
+	if _, err := ReadFileHeader(r); err == nil {
+		t.Fatal("expected error reading a batch header")
+	}
+	if r.Len() != RecordLength {
+		t.Errorf("ReadFileHeader left %d of %d bytes to read", r.Len(), RecordLength)
+	}
+}
+
+func TestReaderReadContext(t *testing.T) {
+	f, err := os.Open("./testdata/ppd-debit.ach")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	r := NewReader(f)
+	if _, err := r.ReadContext(context.Background()); err != nil {
+		t.Fatalf("%T: %s", err, err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	r = NewReader(strings.NewReader(strings.Repeat("1", RecordLength)))
+	if _, err := r.ReadContext(ctx); err != context.Canceled {
+		t.Errorf("expected context.Canceled got: %v", err)
+	}
+}
+
+// mockFileLong returns a created file of more than contextCheckLines records
+func mockFileLong(t *testing.T) *File {
+	batch := NewBatchPPD()
+	batch.SetHeader(mockBatchHeader())
+	for i := 0; i < contextCheckLines+10; i++ {
+		batch.AddEntry(mockEntryDetail())
+	}
+	if err := batch.Create(); err != nil {
+		t.Fatalf("%T: %s", err, err)
+	}
+	file := NewFile().SetHeader(mockFileHeader())
+	file.AddBatch(batch)
+	if err := file.Create(); err != nil {
+		t.Fatalf("%T: %s", err, err)
+	}
+	return file
+}
+
+// TestReaderLongLine ensures a file longer than the default bufio.Scanner buffer can be read when
+// it is written as one line
+func TestReaderLongLine(t *testing.T) {
+	b := &bytes.Buffer{}
+	if err := NewWriter(b, WriterLineEnding(LineEndingNone)).WriteAll([]*File{mockFileLong(t)}); err != nil {
+		t.Fatalf("%T: %s", err, err)
+	}
+	file, err := NewReader(strings.NewReader(b.String())).Read()
+	if err != nil {
+		t.Fatalf("%T: %s", err, err)
+	}
+	if n := len(file.Batches[0].GetEntries()); n != contextCheckLines+10 {
+		t.Errorf("expected %d entries got: %d", contextCheckLines+10, n)
+	}
+}
+
+// countdownContext is a context that is canceled after its Err method has been called n times
+type countdownContext struct {
+	context.Context
+	n int
+}
+
+func (c *countdownContext) Err() error {
+	c.n--
+	if c.n < 0 {
+		return context.Canceled
+	}
+	return nil
+}
+
+// TestReaderReadContextLongLine ensures the context is checked between the records of a file
+// written as one line
+func TestReaderReadContextLongLine(t *testing.T) {
+	b := &bytes.Buffer{}
+	if err := NewWriter(b, WriterLineEnding(LineEndingNone)).WriteAll([]*File{mockFileLong(t)}); err != nil {
+		t.Fatalf("%T: %s", err, err)
+	}
+	ctx := &countdownContext{Context: context.Background(), n: 1}
+	if _, err := NewReader(strings.NewReader(b.String())).ReadContext(ctx); err != context.Canceled {
+		t.Errorf("expected context.Canceled got: %v", err)
+	}
+}
+
+// errReader returns its err once the data has been read
+type errReader struct {
+	r   io.Reader
+	err error
+}
+
+func (e *errReader) Read(p []byte) (int, error) {
+	n, err := e.r.Read(p)
+	if err == io.EOF {
+		return n, e.err
+	}
+	return n, err
+}
+
+// TestReaderScannerError ensures an error reading the underlying io.Reader is returned
+func TestReaderScannerError(t *testing.T) {
+	b := &bytes.Buffer{}
+	if err := NewWriter(b).WriteAll([]*File{mockFilePPD()}); err != nil {
+		t.Fatalf("%T: %s", err, err)
+	}
+	readErr := errors.New("mock read error")
+	if _, err := NewReader(&errReader{r: strings.NewReader(b.String()), err: readErr}).Read(); err != readErr {
+		t.Errorf("expected %v got: %v", readErr, err)
+	}
+}
+
+func TestReaderBlockingFactor(t *testing.T) {
+	b := &bytes.Buffer{}
+	if err := NewWriter(b).WriteAll([]*File{mockFilePPD()}); err != nil {
+		t.Fatalf("%T: %s", err, err)
+	}
+	if _, err := NewReader(strings.NewReader(b.String()), ReaderBlockingFactor(BlockingFactor)).Read(); err != nil {
+		t.Errorf("%T: %s", err, err)
+	}
+
+	b.Reset()
+	if err := NewWriter(b, WriterBlockingFactor(8)).WriteAll([]*File{mockFilePPD()}); err != nil {
+		t.Fatalf("%T: %s", err, err)
+	}
+	if _, err := NewReader(strings.NewReader(b.String()), ReaderBlockingFactor(8)).Read(); err != nil {
+		t.Errorf("%T: %s", err, err)
+	}
+	if _, err := NewReader(strings.NewReader(b.String())).Read(); err != nil {
+		t.Errorf("%T: %s", err, err)
+	}
+	_, err := NewReader(strings.NewReader(b.String()), ReaderBlockingFactor(BlockingFactor)).Read()
+	if p, ok := err.(*ParseError); ok {
+		if e, ok := p.Err.(*FileError); ok {
+			if e.FieldName != "BlockCount" {
+				t.Errorf("%T: %s", e, e)
+			}
+		} else {
+			t.Errorf("%T: %s", p.Err, p.Err)
+		}
+	} else {
+		t.Errorf("expected ParseError got: %v", err)
+	}
+
+	// the padding fills the block but the File Control BlockCount is for blocks of 10 records
+	lines := strings.Split(b.String(), "\n")
+	lines[4] = lines[4][:7] + "000002" + lines[4][13:]
+	_, err = NewReader(strings.NewReader(strings.Join(lines, "\n")), ReaderBlockingFactor(8)).Read()
+	if p, ok := err.(*ParseError); ok {
+		if e, ok := p.Err.(*FileError); !ok || e.FieldName != "BlockCount" {
+			t.Errorf("%T: %s", p.Err, p.Err)
+		}
+	} else {
+		t.Errorf("expected ParseError got: %v", err)
+	}
+}
+
+// TestReaderAllowZeroAmountPrenotes ensures a zero amount prenote is only read with AllowZeroAmountPrenotes
+func TestReaderAllowZeroAmountPrenotes(t *testing.T) {
+	batch := mockBatchWEB()
+	batch.SetAllowZeroAmountPrenotes(true)
+	entry := batch.GetEntries()[0]
+	entry.TransactionCode = 23
+	entry.Amount = 0
+	if err := batch.Create(); err != nil {
+		t.Fatalf("%T: %s", err, err)
+	}
+	file := NewFile().SetHeader(mockFileHeader())
+	file.AddBatch(batch)
+	if err := file.Create(); err != nil {
+		t.Fatalf("%T: %s", err, err)
+	}
+	b := &bytes.Buffer{}
+	if err := NewWriter(b).WriteAll([]*File{file}); err != nil {
+		t.Fatalf("%T: %s", err, err)
+	}
+	if _, err := NewReader(strings.NewReader(b.String())).Read(); err == nil {
+		t.Error("expected error for a zero amount prenote")
+	}
+	read, err := NewReader(strings.NewReader(b.String()), AllowZeroAmountPrenotes()).Read()
+	if err != nil {
+		t.Fatalf("%T: %s", err, err)
+	}
+	if read.Batches[0].GetEntries()[0].Amount != 0 {
+		t.Errorf("Amount got: %d", read.Batches[0].GetEntries()[0].Amount)
+	}
+}
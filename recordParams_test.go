@@ -3,6 +3,7 @@ package ach
 import (
 	"bytes"
 	"testing"
+	"time"
 )
 
 func TestFileParam(t *testing.T) {
@@ -110,6 +111,7 @@ func TestBuildFileParam(t *testing.T) {
 		CompanyIdentification:   "123456789",
 		CompanyEntryDescription: "Trans. Description",
 		CompanyDescriptiveDate:  "Oct 23",
+		EffectiveEntryDate:      time.Now().AddDate(0, 0, 1).Format("060102"),
 		ODFIIdentification:      "123456789"})
 
 	// To create an entry
@@ -151,6 +153,7 @@ func TestBuildFileParam(t *testing.T) {
 		CompanyIdentification:   "123456789",
 		CompanyEntryDescription: "monthly subscription",
 		CompanyDescriptiveDate:  "Oct 23",
+		EffectiveEntryDate:      time.Now().AddDate(0, 0, 1).Format("060102"),
 		ODFIIdentification:      "123456789"})
 
 	// Add an entry and define if it is a single or reoccuring payment
@@ -0,0 +1,118 @@
+// Copyright 2017 The ACH Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package ach
+
+import (
+	"time"
+)
+
+// Severity levels of a ReportItem
+const (
+	// SeverityError is a NACHA rule violation that makes the file invalid
+	SeverityError = "error"
+	// SeverityWarning is a likely problem that does not make the file invalid
+	SeverityWarning = "warning"
+)
+
+// ReportItem is an error found validating a record with the severity of the error.
+type ReportItem struct {
+	Severity string
+	Err      error
+}
+
+// EntryReport holds the errors found validating an entry and its addenda.
+type EntryReport struct {
+	TraceNumber int
+	Items       []ReportItem
+}
+
+// BatchReport holds the errors found validating a batch and the reports of its entries.
+type BatchReport struct {
+	BatchNumber int
+	Items       []ReportItem
+	Entries     []EntryReport
+}
+
+// Report is the result of validating every record of a file grouped by record.
+type Report struct {
+	Items   []ReportItem
+	Batches []BatchReport
+}
+
+// HasErrors returns true if the report contains an item with SeverityError.
+func (r *Report) HasErrors() bool {
+	has := func(items []ReportItem) bool {
+		for _, item := range items {
+			if item.Severity == SeverityError {
+				return true
+			}
+		}
+		return false
+	}
+	if has(r.Items) {
+		return true
+	}
+	for _, batch := range r.Batches {
+		if has(batch.Items) {
+			return true
+		}
+		for _, entry := range batch.Entries {
+			if has(entry.Items) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// ValidationReport validates every record of the file instead of stopping at the first error
+// and returns the errors grouped by file, batch and entry. Batches report their header and
+// control checks and entries report their own and their addenda checks so each error is listed once. The checks of the WarnOn options of
+// ValidateOpts are reported with SeverityWarning.
+func (f *File) ValidationReport() *Report {
+	r := &Report{}
+	r.Items = appendReportItem(r.Items, SeverityError, f.Header.Validate())
+	r.Items = appendReportItem(r.Items, SeverityError, f.Control.Validate())
+	for _, check := range []func() error{f.isBatchCount, f.isEffectiveEntryDates, f.isEntryAddendaCount, f.isADVSegregated, f.isFileAmount, f.isEntryHash} {
+		r.Items = appendReportItem(r.Items, SeverityError, check())
+	}
+	r.Items = appendReportItem(r.Items, SeverityWarning, f.Header.isFileCreationDateFuture(time.Now()))
+	r.Items = appendReportItem(r.Items, SeverityWarning, f.isDuplicateBatchHeader())
+
+	for _, batch := range f.Batches {
+		br := BatchReport{BatchNumber: batch.GetHeader().BatchNumber}
+		br.Items = appendReportItem(br.Items, SeverityError, batch.GetHeader().Validate())
+		if b, ok := batch.(interface{ controlErrors() []error }); ok {
+			for _, err := range b.controlErrors() {
+				br.Items = appendReportItem(br.Items, SeverityError, err)
+			}
+		}
+		for _, entry := range batch.GetEntries() {
+			er := EntryReport{TraceNumber: entry.TraceNumber}
+			er.Items = appendReportItem(er.Items, SeverityError, entry.Validate())
+			for i := range entry.Addendum {
+				er.Items = appendReportItem(er.Items, SeverityError, entry.Addendum[i].Validate())
+			}
+			for i := range entry.ReturnAddendum {
+				er.Items = appendReportItem(er.Items, SeverityError, entry.ReturnAddendum[i].Validate())
+			}
+			if len(er.Items) > 0 {
+				br.Entries = append(br.Entries, er)
+			}
+		}
+		if len(br.Items) > 0 || len(br.Entries) > 0 {
+			r.Batches = append(r.Batches, br)
+		}
+	}
+	return r
+}
+
+// appendReportItem appends err to items with severity if err is not nil
+func appendReportItem(items []ReportItem, severity string, err error) []ReportItem {
+	if err == nil {
+		return items
+	}
+	return append(items, ReportItem{Severity: severity, Err: err})
+}
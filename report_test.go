@@ -0,0 +1,85 @@
+// Copyright 2017 The ACH Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package ach
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFileValidationReport(t *testing.T) {
+	file := mockFilePPD()
+	r := file.ValidationReport()
+	if r.HasErrors() || len(r.Items) != 0 || len(r.Batches) != 0 {
+		t.Errorf("ValidationReport expected an empty report got: %+v", r)
+	}
+
+	file.Header.FileCreationDate = time.Now().AddDate(0, 0, 2)
+	entry := file.Batches[0].GetEntries()[0]
+	entry.IndividualName = "Wade®"
+	r = file.ValidationReport()
+	if !r.HasErrors() {
+		t.Error("ValidationReport expected errors")
+	}
+	if len(r.Items) != 1 || r.Items[0].Severity != SeverityWarning {
+		t.Errorf("ValidationReport expected a creation date warning got: %+v", r.Items)
+	}
+	if len(r.Batches) != 1 || len(r.Batches[0].Entries) != 1 {
+		t.Fatalf("ValidationReport expected an entry report got: %+v", r.Batches)
+	}
+	er := r.Batches[0].Entries[0]
+	if er.TraceNumber != entry.TraceNumber || len(er.Items) != 1 || er.Items[0].Severity != SeverityError {
+		t.Errorf("ValidationReport entry report got: %+v", er)
+	}
+	if e, ok := er.Items[0].Err.(*FieldError); ok {
+		if e.FieldName != "IndividualName" {
+			t.Errorf("%T: %s", e, e)
+		}
+	} else {
+		t.Errorf("expected FieldError got: %v", er.Items[0].Err)
+	}
+}
+
+func TestFileValidationReportCollectsErrors(t *testing.T) {
+	file := mockFilePPD()
+	file.Control.BatchCount = 5
+	file.Control.TotalDebitEntryDollarAmountInFile = 1
+	bc := file.Batches[0].GetControl()
+	bc.EntryAddendaCount = 9
+	bc.TotalCreditEntryDollarAmount = 1
+	entry := file.Batches[0].GetEntries()[0]
+	entry.IndividualName = "Wade®"
+
+	r := file.ValidationReport()
+	fields := func(items []ReportItem) []string {
+		var names []string
+		for _, item := range items {
+			switch e := item.Err.(type) {
+			case *FileError:
+				names = append(names, e.FieldName)
+			case *BatchError:
+				names = append(names, e.FieldName)
+			case *FieldError:
+				names = append(names, e.FieldName)
+			}
+		}
+		return names
+	}
+	if got := fields(r.Items); len(got) != 3 || got[0] != "BatchCount" || got[1] != "EntryAddendaCount" || got[2] != "TotalDebitEntryDollarAmountInFile" {
+		t.Errorf("ValidationReport file items got: %v", got)
+	}
+	if len(r.Batches) != 1 {
+		t.Fatalf("ValidationReport expected a batch report got: %+v", r.Batches)
+	}
+	if got := fields(r.Batches[0].Items); len(got) != 2 || got[0] != "EntryAddendaCount" || got[1] != "TotalCreditEntryDollarAmount" {
+		t.Errorf("ValidationReport batch items got: %v", got)
+	}
+	if len(r.Batches[0].Entries) != 1 {
+		t.Fatalf("ValidationReport expected an entry report got: %+v", r.Batches[0].Entries)
+	}
+	if got := fields(r.Batches[0].Entries[0].Items); len(got) != 1 || got[0] != "IndividualName" {
+		t.Errorf("ValidationReport entry items got: %v", got)
+	}
+}
@@ -4,6 +4,7 @@ package ach
 
 import (
 	"flag"
+	"fmt"
 	"strings"
 	"time"
 )
@@ -12,6 +13,48 @@ const (
 	timeFormat = "060102" // for date of death
 )
 
+// returnCodeEntryTypes are the return codes that can only be used to return debit or credit
+// entries. Return codes that are not listed can be used for either.
+var returnCodeEntryTypes = map[string]string{
+	"R01": "debit",
+	"R05": "debit",
+	"R07": "debit",
+	"R08": "debit",
+	"R09": "debit",
+	"R10": "debit",
+	"R11": "debit",
+	"R23": "credit",
+	"R29": "debit",
+	"R51": "debit",
+	"R52": "debit",
+	"R53": "debit",
+}
+
+// Errors specific to a ReturnAddenda record
+var (
+	msgReturnAddendaDishonored = "%v is not a dishonored return code R61, R62, R67, R68, R69 or R70"
+	msgReturnAddendaContested  = "%v is not a contested dishonored return code R71 through R77"
+	msgReturnAddendaAddendum   = "return addenda can not be added to an entry with addenda"
+)
+
+// isDishonoredReturnCode returns true if code is used by the ODFI to dishonor a return entry
+func isDishonoredReturnCode(code string) bool {
+	switch code {
+	case "R61", "R62", "R67", "R68", "R69", "R70":
+		return true
+	}
+	return false
+}
+
+// isContestedReturnCode returns true if code is used by the RDFI to contest a dishonored return
+func isContestedReturnCode(code string) bool {
+	switch code {
+	case "R71", "R72", "R73", "R74", "R75", "R76", "R77":
+		return true
+	}
+	return false
+}
+
 func init() {
 	flag.Lookup("alsologtostderr").Value.Set("true")
 }
@@ -30,14 +73,65 @@ type ReturnAddenda struct {
 	AddendaInformation string
 	Trace              int
 
+	// Dishonored holds the fields of a dishonored return. It is set for the return codes
+	// R61, R62 and R67 through R70.
+	Dishonored *DishonoredReturn
+	// Contested holds the fields of a contested dishonored return. It is set for the return
+	// codes R71 through R77.
+	Contested *ContestedReturn
+	// Raw is the record as it was read when the Reader has the PreserveRawRecords option
+	Raw *RawRecord
+
 	// validator is composed for data validation
 	validator
 	// converters is composed for ACH to GoLang Converters
 	converters
 }
 
+// DishonoredReturn are the fields of a dishonored return addenda. The ODFI dishonors a return
+// entry it received by sending it back to the RDFI.
+type DishonoredReturn struct {
+	// ReturnTrace is the trace number of the return entry being dishonored
+	ReturnTrace int
+	// ReturnSettlementDate is the julian settlement date of the return entry
+	ReturnSettlementDate string
+	// ReturnReasonCode is the reason code of the return entry without the leading R. For example "01"
+	ReturnReasonCode string
+}
+
+// ContestedReturn are the fields of a contested dishonored return addenda. The RDFI contests a
+// dishonored return it received by sending it back to the ODFI.
+type ContestedReturn struct {
+	// DateOriginalEntryReturned is the date the RDFI returned the original entry
+	DateOriginalEntryReturned *time.Time
+	// OriginalSettlementDate is the julian settlement date of the original entry
+	OriginalSettlementDate string
+	// ReturnTrace is the trace number of the return entry
+	ReturnTrace int
+	// ReturnSettlementDate is the julian settlement date of the return entry
+	ReturnSettlementDate string
+	// ReturnReasonCode is the reason code of the return entry without the leading R. For example "01"
+	ReturnReasonCode string
+	// DishonoredReturnTrace is the trace number of the dishonored return entry being contested
+	DishonoredReturnTrace int
+	// DishonoredReturnSettlementDate is the julian settlement date of the dishonored return entry
+	DishonoredReturnSettlementDate string
+	// DishonoredReturnReasonCode is the reason code of the dishonored return without the leading R. For example "69"
+	DishonoredReturnReasonCode string
+}
+
+// NewReturnAddenda returns a new ReturnAddenda with default values for none exported fields
+func NewReturnAddenda() ReturnAddenda {
+	return ReturnAddenda{
+		recordType: "7",
+		TypeCode:   "99",
+	}
+}
+
 // Parse takes the input record string and parses the ReturnAddenda values
+// A record shorter than RecordLength is padded with spaces.
 func (returnAddenda *ReturnAddenda) Parse(record string) {
+	record = returnAddenda.padRecord(record)
 	// 1-1 Always "7"
 	returnAddenda.recordType = "7"
 	// 2-3 Defines the specific explanation and format for the addenda information contained in the same record
@@ -46,6 +140,14 @@ func (returnAddenda *ReturnAddenda) Parse(record string) {
 	returnAddenda.ReturnCode = record[3:6]
 	// 7-21
 	returnAddenda.OriginalTrace = returnAddenda.parseNumField(record[6:21])
+	switch {
+	case isDishonoredReturnCode(returnAddenda.ReturnCode):
+		returnAddenda.parseDishonored(record)
+		return
+	case isContestedReturnCode(returnAddenda.ReturnCode):
+		returnAddenda.parseContested(record)
+		return
+	}
 	// 22-27, might be a date or blank
 	result, err := time.Parse(timeFormat, strings.TrimSpace(record[21:27]))
 	returnAddenda.DateOfDeath = &result
@@ -60,8 +162,123 @@ func (returnAddenda *ReturnAddenda) Parse(record string) {
 	returnAddenda.Trace = returnAddenda.parseNumField(record[79:94])
 }
 
-// implement later
+// parseDishonored parses positions 22-94 of a dishonored return addenda record
+func (returnAddenda *ReturnAddenda) parseDishonored(record string) {
+	// 22-27 reserved
+	// 28-35
+	returnAddenda.OriginalDFI = record[27:35]
+	// 36-38 reserved
+	returnAddenda.Dishonored = &DishonoredReturn{
+		// 39-53
+		ReturnTrace: returnAddenda.parseNumField(record[38:53]),
+		// 54-56
+		ReturnSettlementDate: strings.TrimSpace(record[53:56]),
+		// 57-58
+		ReturnReasonCode: strings.TrimSpace(record[56:58]),
+	}
+	// 59-79
+	returnAddenda.AddendaInformation = strings.TrimSpace(record[58:79])
+	// 80-94
+	returnAddenda.Trace = returnAddenda.parseNumField(record[79:94])
+}
+
+// parseContested parses positions 22-94 of a contested dishonored return addenda record
+func (returnAddenda *ReturnAddenda) parseContested(record string) {
+	contested := &ContestedReturn{}
+	// 22-27, might be a date or blank
+	if result, err := time.Parse(timeFormat, strings.TrimSpace(record[21:27])); err == nil {
+		contested.DateOriginalEntryReturned = &result
+	}
+	// 28-35
+	returnAddenda.OriginalDFI = record[27:35]
+	// 36-38
+	contested.OriginalSettlementDate = strings.TrimSpace(record[35:38])
+	// 39-53
+	contested.ReturnTrace = returnAddenda.parseNumField(record[38:53])
+	// 54-56
+	contested.ReturnSettlementDate = strings.TrimSpace(record[53:56])
+	// 57-58
+	contested.ReturnReasonCode = strings.TrimSpace(record[56:58])
+	// 59-73
+	contested.DishonoredReturnTrace = returnAddenda.parseNumField(record[58:73])
+	// 74-76
+	contested.DishonoredReturnSettlementDate = strings.TrimSpace(record[73:76])
+	// 77-78
+	contested.DishonoredReturnReasonCode = strings.TrimSpace(record[76:78])
+	// 79 reserved
+	returnAddenda.Contested = contested
+	// 80-94
+	returnAddenda.Trace = returnAddenda.parseNumField(record[79:94])
+}
+
+// String writes the ReturnAddenda struct to a 94 character string. The layout of positions
+// 22-79 depends on whether the addenda is a return, dishonored return or contested dishonored return.
+func (returnAddenda *ReturnAddenda) String() string {
+	var body string
+	switch {
+	case returnAddenda.Dishonored != nil:
+		d := returnAddenda.Dishonored
+		body = fmt.Sprintf("%v%v%v%v%v%v%v",
+			strings.Repeat(" ", 6),
+			returnAddenda.alphaField(returnAddenda.OriginalDFI, 8),
+			strings.Repeat(" ", 3),
+			returnAddenda.numericField(d.ReturnTrace, 15),
+			returnAddenda.alphaField(d.ReturnSettlementDate, 3),
+			returnAddenda.alphaField(d.ReturnReasonCode, 2),
+			returnAddenda.alphaField(returnAddenda.AddendaInformation, 21))
+	case returnAddenda.Contested != nil:
+		c := returnAddenda.Contested
+		body = fmt.Sprintf("%v%v%v%v%v%v%v%v%v%v",
+			returnAddenda.dateField(c.DateOriginalEntryReturned),
+			returnAddenda.alphaField(returnAddenda.OriginalDFI, 8),
+			returnAddenda.alphaField(c.OriginalSettlementDate, 3),
+			returnAddenda.numericField(c.ReturnTrace, 15),
+			returnAddenda.alphaField(c.ReturnSettlementDate, 3),
+			returnAddenda.alphaField(c.ReturnReasonCode, 2),
+			returnAddenda.numericField(c.DishonoredReturnTrace, 15),
+			returnAddenda.alphaField(c.DishonoredReturnSettlementDate, 3),
+			returnAddenda.alphaField(c.DishonoredReturnReasonCode, 2),
+			" ")
+	default:
+		body = fmt.Sprintf("%v%v%v",
+			returnAddenda.dateField(returnAddenda.DateOfDeath),
+			returnAddenda.alphaField(returnAddenda.OriginalDFI, 8),
+			returnAddenda.alphaField(returnAddenda.AddendaInformation, 44))
+	}
+	return fmt.Sprintf("%v%v%v%v%v%v",
+		returnAddenda.recordType,
+		returnAddenda.TypeCode,
+		returnAddenda.alphaField(returnAddenda.ReturnCode, 3),
+		returnAddenda.numericField(returnAddenda.OriginalTrace, 15),
+		body,
+		returnAddenda.numericField(returnAddenda.Trace, 15))
+}
+
+// dateField returns t formatted as YYMMDD or six spaces when t is nil
+func (returnAddenda *ReturnAddenda) dateField(t *time.Time) string {
+	if t == nil {
+		return strings.Repeat(" ", 6)
+	}
+	return t.Format(timeFormat)
+}
+
+// Validate checks that the dishonored and contested fields are only set for the return codes
+// that use them. Other return addenda fields are not validated yet.
 func (returnAddenda *ReturnAddenda) Validate() error {
+	if returnAddenda.Dishonored != nil && !isDishonoredReturnCode(returnAddenda.ReturnCode) {
+		msg := fmt.Sprintf(msgReturnAddendaDishonored, returnAddenda.ReturnCode)
+		return &FieldError{FieldName: "ReturnCode", Value: returnAddenda.ReturnCode, Msg: msg}
+	}
+	if returnAddenda.Contested != nil && !isContestedReturnCode(returnAddenda.ReturnCode) {
+		msg := fmt.Sprintf(msgReturnAddendaContested, returnAddenda.ReturnCode)
+		return &FieldError{FieldName: "ReturnCode", Value: returnAddenda.ReturnCode, Msg: msg}
+	}
+	if isDishonoredReturnCode(returnAddenda.ReturnCode) && returnAddenda.Dishonored == nil {
+		return &FieldError{FieldName: "Dishonored", Value: returnAddenda.ReturnCode, Msg: msgFieldInclusion}
+	}
+	if isContestedReturnCode(returnAddenda.ReturnCode) && returnAddenda.Contested == nil {
+		return &FieldError{FieldName: "Contested", Value: returnAddenda.ReturnCode, Msg: msgFieldInclusion}
+	}
 	return nil
 }
 
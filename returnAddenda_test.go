@@ -0,0 +1,168 @@
+// Copyright 2017 The ACH Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package ach
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func mockDishonoredReturn() DishonoredReturn {
+	return DishonoredReturn{
+		ReturnTrace:          91012980000066,
+		ReturnSettlementDate: "179",
+		ReturnReasonCode:     "01",
+	}
+}
+
+func mockContestedReturn() ContestedReturn {
+	returned := time.Date(2018, time.June, 27, 0, 0, 0, 0, time.UTC)
+	return ContestedReturn{
+		DateOriginalEntryReturned:      &returned,
+		OriginalSettlementDate:         "176",
+		ReturnTrace:                    91012980000066,
+		ReturnSettlementDate:           "179",
+		ReturnReasonCode:               "01",
+		DishonoredReturnTrace:          62000010000012,
+		DishonoredReturnSettlementDate: "182",
+		DishonoredReturnReasonCode:     "69",
+	}
+}
+
+func TestReturnAddendaDishonoredParse(t *testing.T) {
+	entry := mockEntryDetail()
+	if err := entry.AddDishonoredReturn("R69", 62000010000001, "09101298", mockDishonoredReturn()); err != nil {
+		t.Fatalf("%T: %s", err, err)
+	}
+	if entry.Category != CategoryReturn || !entry.HasAddenda() {
+		t.Errorf("entry category %v addenda indicator %v", entry.Category, entry.AddendaRecordIndicator)
+	}
+	record := entry.ReturnAddendum[0].String()
+	if len(record) != RecordLength {
+		t.Fatalf("dishonored return length %d: %q", len(record), record)
+	}
+	parsed := ReturnAddenda{}
+	parsed.Parse(record)
+	if parsed.Dishonored == nil || *parsed.Dishonored != mockDishonoredReturn() {
+		t.Errorf("Dishonored got %+v", parsed.Dishonored)
+	}
+	if parsed.OriginalTrace != 62000010000001 || parsed.OriginalDFI != "09101298" {
+		t.Errorf("OriginalTrace %v OriginalDFI %v", parsed.OriginalTrace, parsed.OriginalDFI)
+	}
+	if parsed.String() != record {
+		t.Errorf("got:\n%s\nwant:\n%s", parsed.String(), record)
+	}
+}
+
+func TestReturnAddendaContestedParse(t *testing.T) {
+	entry := mockEntryDetail()
+	if err := entry.AddContestedReturn("R72", 62000010000001, "09101298", mockContestedReturn()); err != nil {
+		t.Fatalf("%T: %s", err, err)
+	}
+	record := entry.ReturnAddendum[0].String()
+	if len(record) != RecordLength {
+		t.Fatalf("contested return length %d: %q", len(record), record)
+	}
+	parsed := ReturnAddenda{}
+	parsed.Parse(record)
+	c := parsed.Contested
+	if c == nil {
+		t.Fatal("expected Contested to be parsed")
+	}
+	if !c.DateOriginalEntryReturned.Equal(*mockContestedReturn().DateOriginalEntryReturned) ||
+		c.DishonoredReturnTrace != 62000010000012 || c.DishonoredReturnReasonCode != "69" {
+		t.Errorf("Contested got %+v", c)
+	}
+	if parsed.String() != record {
+		t.Errorf("got:\n%s\nwant:\n%s", parsed.String(), record)
+	}
+}
+
+func TestReturnAddendaReturnCodeStage(t *testing.T) {
+	entry := mockEntryDetail()
+	for _, code := range []string{"R01", "R72"} {
+		if err := entry.AddDishonoredReturn(code, 62000010000001, "09101298", mockDishonoredReturn()); err != nil {
+			if e, ok := err.(*FieldError); ok {
+				if e.FieldName != "ReturnCode" {
+					t.Errorf("%T: %s", err, err)
+				}
+			} else {
+				t.Errorf("%T: %s", err, err)
+			}
+		} else {
+			t.Errorf("expected error for dishonored return code %v", code)
+		}
+	}
+	for _, code := range []string{"R01", "R69"} {
+		if err := entry.AddContestedReturn(code, 62000010000001, "09101298", mockContestedReturn()); err != nil {
+			if e, ok := err.(*FieldError); ok {
+				if e.FieldName != "ReturnCode" {
+					t.Errorf("%T: %s", err, err)
+				}
+			} else {
+				t.Errorf("%T: %s", err, err)
+			}
+		} else {
+			t.Errorf("expected error for contested return code %v", code)
+		}
+	}
+	if entry.ReturnAddendum != nil {
+		t.Errorf("invalid return addenda were added: %+v", entry.ReturnAddendum)
+	}
+}
+
+func TestReturnAddendaValidateDishonored(t *testing.T) {
+	returnAddenda := NewReturnAddenda()
+	returnAddenda.ReturnCode = "R68"
+	if err := returnAddenda.Validate(); err != nil {
+		if e, ok := err.(*FieldError); ok {
+			if e.FieldName != "Dishonored" {
+				t.Errorf("%T: %s", err, err)
+			}
+		} else {
+			t.Errorf("%T: %s", err, err)
+		}
+	} else {
+		t.Error("expected error for dishonored return code without dishonored fields")
+	}
+}
+
+func TestReturnAddendaDishonoredReadWrite(t *testing.T) {
+	file := mockFilePPD()
+	entry := file.Batches[0].GetEntries()[0]
+	if err := entry.AddDishonoredReturn("R69", 62000010000001, "09101298", mockDishonoredReturn()); err != nil {
+		t.Fatalf("%T: %s", err, err)
+	}
+	if err := file.Batches[0].Create(); err != nil {
+		t.Fatalf("%T: %s", err, err)
+	}
+	if err := file.Create(); err != nil {
+		t.Fatalf("%T: %s", err, err)
+	}
+	b := &bytes.Buffer{}
+	if err := NewWriter(b).WriteAll([]*File{file}); err != nil {
+		t.Fatalf("%T: %s", err, err)
+	}
+	read, err := NewReader(strings.NewReader(b.String())).Read()
+	if err != nil {
+		t.Fatalf("%T: %s", err, err)
+	}
+	returns := read.Batches[0].GetEntries()[0].ReturnAddendum
+	if len(returns) != 1 || returns[0].Dishonored == nil {
+		t.Fatalf("expected a dishonored return to be read got: %+v", returns)
+	}
+	if *returns[0].Dishonored != mockDishonoredReturn() || returns[0].Trace != entry.TraceNumber {
+		t.Errorf("read dishonored return %+v", returns[0])
+	}
+	out := &bytes.Buffer{}
+	if err := NewWriter(out).WriteAll([]*File{&read}); err != nil {
+		t.Fatalf("%T: %s", err, err)
+	}
+	if out.String() != b.String() {
+		t.Errorf("dishonored return file does not round trip\ngot:\n%s\nwant:\n%s", out, b)
+	}
+}
@@ -0,0 +1,108 @@
+// Copyright 2017 The ACH Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package ach
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// SameDayEntryLimit is the largest Amount in cents of an entry eligible for Same-Day ACH.
+const SameDayEntryLimit = 100000000
+
+// sameDayMarker begins a CompanyDescriptiveDate, such as "SD1300", that requests same day settlement
+const sameDayMarker = "SD"
+
+var (
+	msgSameDayEntryLimit     = "%v exceeds the same day entry limit %v"
+	msgSameDayPastEffective  = "effective entry date %v is before the file creation date %v"
+	msgSameDaySettlementDate = "%q is not a julian settlement date for a same day batch"
+	msgNextDaySettlementDate = "%v is set for a batch that does not settle same day"
+)
+
+// SameDayViolation is a batch settling same day, or an entry in it, that is not eligible for Same-Day ACH.
+type SameDayViolation struct {
+	// BatchNumber of the batch containing the entry
+	BatchNumber int
+	// Entry that is not eligible for Same-Day ACH or nil when the batch header violates the rule
+	Entry *EntryDetail
+	// Reason describes the rule violated by the batch or entry
+	Reason string
+}
+
+// SameDayViolations returns the same day rules violated by each batch that settles same day with
+// one violation for each rule. A batch settles same day as isSameDay decides. An entry with an
+// Amount over SameDayEntryLimit is reported with the entry. An EffectiveEntryDate before the
+// FileCreationDate and a SettlementDate that is set but not a julian date are reported for the batch.
+func (f *File) SameDayViolations() []SameDayViolation {
+	var violations []SameDayViolation
+	for _, batch := range f.Batches {
+		bh := batch.GetHeader()
+		if !bh.isSameDay(f.Header.FileCreationDate) {
+			continue
+		}
+		if !bh.EffectiveEntryDate.IsZero() && truncateDay(bh.EffectiveEntryDate).Before(truncateDay(f.Header.FileCreationDate)) {
+			reason := fmt.Sprintf(msgSameDayPastEffective, bh.EffectiveEntryDateField(), f.Header.FileCreationDateField())
+			violations = append(violations, SameDayViolation{BatchNumber: bh.BatchNumber, Reason: reason})
+		}
+		if date := bh.SettlementDate(); date != "" && !bh.isJulianDate(date) {
+			reason := fmt.Sprintf(msgSameDaySettlementDate, date)
+			violations = append(violations, SameDayViolation{BatchNumber: bh.BatchNumber, Reason: reason})
+		}
+		for _, entry := range batch.GetEntries() {
+			if entry.Amount <= SameDayEntryLimit {
+				continue
+			}
+			reason := fmt.Sprintf(msgSameDayEntryLimit, entry.FormattedAmount(), f.formatDollars(SameDayEntryLimit))
+			violations = append(violations, SameDayViolation{BatchNumber: bh.BatchNumber, Entry: entry, Reason: reason})
+		}
+	}
+	return violations
+}
+
+// isSameDay returns true if the batch settles same day. The CompanyDescriptiveDate begins with
+// the "SD" marker or the EffectiveEntryDate is on or before created.
+func (bh *BatchHeader) isSameDay(created time.Time) bool {
+	if strings.HasPrefix(bh.CompanyDescriptiveDate, sameDayMarker) {
+		return true
+	}
+	if bh.EffectiveEntryDate.IsZero() {
+		return false
+	}
+	return !truncateDay(bh.EffectiveEntryDate).After(truncateDay(created))
+}
+
+// truncateDay returns the start of the day of t in UTC so dates are compared without their times
+func truncateDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// isSameDaySettlementDate checks that every batch settling same day has a julian SettlementDate
+// of 001 through 366 and that other batches leave the SettlementDate blank.
+func (f *File) isSameDaySettlementDate() error {
+	for i, batch := range f.Batches {
+		bh := batch.GetHeader()
+		date := bh.SettlementDate()
+		if !bh.isSameDay(f.Header.FileCreationDate) {
+			if date != "" {
+				msg := fmt.Sprintf(msgNextDaySettlementDate, date)
+				return f.batchError(i, "SettlementDate", msg)
+			}
+			continue
+		}
+		if !bh.isJulianDate(date) {
+			msg := fmt.Sprintf(msgSameDaySettlementDate, date)
+			return f.batchError(i, "SettlementDate", msg)
+		}
+	}
+	return nil
+}
+
+// isJulianDate returns true if date is a 3 digit julian date of 001 through 366
+func (bh *BatchHeader) isJulianDate(date string) bool {
+	day := bh.parseNumField(date)
+	return len(date) == 3 && bh.isNumeric(date) == nil && day >= 1 && day <= 366
+}
@@ -0,0 +1,106 @@
+// Copyright 2017 The ACH Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package ach
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFileSameDayViolations(t *testing.T) {
+	file := mockFilePPD()
+	file.Header.FileCreationDate = time.Date(2017, time.November, 2, 9, 30, 0, 0, time.UTC)
+	entry := file.Batches[0].GetEntries()[0]
+	bh := file.Batches[0].GetHeader()
+
+	// future dated batches are not same day
+	bh.EffectiveEntryDate = time.Date(2017, time.November, 3, 0, 0, 0, 0, time.UTC)
+	entry.Amount = SameDayEntryLimit + 1
+	if v := file.SameDayViolations(); len(v) != 0 {
+		t.Errorf("SameDayViolations expected none got: %v", v)
+	}
+
+	bh.EffectiveEntryDate = time.Date(2017, time.November, 2, 0, 0, 0, 0, time.UTC)
+	entry.Amount = SameDayEntryLimit
+	if v := file.SameDayViolations(); len(v) != 0 {
+		t.Errorf("SameDayViolations expected none got: %v", v)
+	}
+
+	entry.Amount = SameDayEntryLimit + 1
+	v := file.SameDayViolations()
+	if len(v) != 1 || v[0].Entry != entry || v[0].BatchNumber != bh.BatchNumber {
+		t.Fatalf("SameDayViolations got: %v", v)
+	}
+	if v[0].Reason != "$1,000,000.01 exceeds the same day entry limit $1,000,000.00" {
+		t.Errorf("SameDayViolations Reason got: %v", v[0].Reason)
+	}
+
+	// each rule is reported on its own
+	entry.Amount = SameDayEntryLimit
+	bh.EffectiveEntryDate = time.Date(2017, time.November, 1, 0, 0, 0, 0, time.UTC)
+	v = file.SameDayViolations()
+	if len(v) != 1 || v[0].Entry != nil || v[0].Reason != "effective entry date 171101 is before the file creation date 171102" {
+		t.Errorf("SameDayViolations expected a past effective date violation got: %v", v)
+	}
+
+	bh.EffectiveEntryDate = time.Date(2017, time.November, 3, 0, 0, 0, 0, time.UTC)
+	bh.CompanyDescriptiveDate = "SD1300"
+	bh.SetSettlementDate("367")
+	v = file.SameDayViolations()
+	if len(v) != 1 || v[0].Entry != nil || v[0].Reason != `"367" is not a julian settlement date for a same day batch` {
+		t.Errorf("SameDayViolations expected a settlement date violation got: %v", v)
+	}
+
+	bh.EffectiveEntryDate = time.Date(2017, time.November, 1, 0, 0, 0, 0, time.UTC)
+	entry.Amount = SameDayEntryLimit + 1
+	if v := file.SameDayViolations(); len(v) != 3 {
+		t.Errorf("SameDayViolations expected three violations got: %v", v)
+	}
+}
+
+func TestFileRequireSameDaySettlementDate(t *testing.T) {
+	file := mockFilePPD()
+	file.Header.FileCreationDate = time.Date(2017, time.November, 2, 9, 30, 0, 0, time.UTC)
+	bh := file.Batches[0].GetHeader()
+	bh.EffectiveEntryDate = time.Date(2017, time.November, 3, 0, 0, 0, 0, time.UTC)
+	opts := &ValidateOpts{RequireSameDaySettlementDate: true}
+	if err := file.ValidateWith(opts); err != nil {
+		t.Errorf("%T: %s", err, err)
+	}
+
+	tests := []struct {
+		descriptiveDate string
+		effective       time.Time
+		settlementDate  string
+		valid           bool
+	}{
+		{"", bh.EffectiveEntryDate, "307", false},
+		{"", time.Date(2017, time.November, 2, 0, 0, 0, 0, time.UTC), "", false},
+		{"", time.Date(2017, time.November, 2, 0, 0, 0, 0, time.UTC), "306", true},
+		{"SD1300", bh.EffectiveEntryDate, "", false},
+		{"SD1300", bh.EffectiveEntryDate, "367", false},
+		{"SD1300", bh.EffectiveEntryDate, "3A6", false},
+		{"SD1300", bh.EffectiveEntryDate, "306", true},
+	}
+	for _, test := range tests {
+		bh.CompanyDescriptiveDate = test.descriptiveDate
+		bh.EffectiveEntryDate = test.effective
+		bh.SetSettlementDate(test.settlementDate)
+		err := file.ValidateWith(opts)
+		if test.valid {
+			if err != nil {
+				t.Errorf("%+v: %s", test, err)
+			}
+			continue
+		}
+		if e, ok := err.(*BatchError); ok {
+			if e.FieldName != "SettlementDate" {
+				t.Errorf("%T: %s", err, err)
+			}
+		} else {
+			t.Errorf("%+v expected BatchError got: %v", test, err)
+		}
+	}
+}
@@ -0,0 +1,64 @@
+// Copyright 2017 The ACH Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package ach
+
+// BatchTotals are the control totals of one batch in Totals.
+type BatchTotals struct {
+	BatchNumber       int
+	TotalDebit        int
+	TotalCredit       int
+	EntryHash         int
+	EntryAddendaCount int
+}
+
+// Net returns the total credit amount minus the total debit amount of the batch.
+func (bt BatchTotals) Net() int {
+	return bt.TotalCredit - bt.TotalDebit
+}
+
+// Totals are the control totals of a file and each of its batches. They are the values Create
+// would write to the batch controls and the File Control.
+type Totals struct {
+	TotalDebit        int
+	TotalCredit       int
+	EntryHash         int
+	EntryAddendaCount int
+	Batches           []BatchTotals
+}
+
+// Net returns the total credit amount minus the total debit amount of the file.
+func (t Totals) Net() int {
+	return t.TotalCredit - t.TotalDebit
+}
+
+// Totals calculates the control totals of the file and its batches from the entries as Create
+// would without changing the file. Batches are numbered by their position in the file as Create
+// numbers them. An error is returned for a batch that can not be created.
+func (f *File) Totals() (Totals, error) {
+	totals := Totals{}
+	for i, batch := range f.Batches {
+		clone, err := cloneBatch(batch)
+		if err != nil {
+			return totals, err
+		}
+		clone.GetHeader().BatchNumber = i + 1
+		if err := clone.Create(); err != nil {
+			return totals, err
+		}
+		bc := clone.GetControl()
+		totals.Batches = append(totals.Batches, BatchTotals{
+			BatchNumber:       i + 1,
+			TotalDebit:        bc.TotalDebitEntryDollarAmount,
+			TotalCredit:       bc.TotalCreditEntryDollarAmount,
+			EntryHash:         bc.EntryHash,
+			EntryAddendaCount: bc.EntryAddendaCount,
+		})
+		totals.TotalDebit += bc.TotalDebitEntryDollarAmount
+		totals.TotalCredit += bc.TotalCreditEntryDollarAmount
+		totals.EntryHash += bc.EntryHash
+		totals.EntryAddendaCount += bc.EntryAddendaCount
+	}
+	return totals, nil
+}
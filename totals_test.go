@@ -0,0 +1,52 @@
+// Copyright 2017 The ACH Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package ach
+
+import (
+	"testing"
+)
+
+func TestFileTotals(t *testing.T) {
+	file := mockFilePPD()
+	debit := mockEntryDetail()
+	debit.TransactionCode = 27
+	debit.Amount = 2500
+	file.Batches[0].AddEntry(debit)
+	file.AddBatch(mockBatchPPD())
+	control := file.Control.String()
+
+	totals, err := file.Totals()
+	if err != nil {
+		t.Fatalf("%T: %s", err, err)
+	}
+	if file.Control.String() != control || file.Batches[0].GetControl().EntryAddendaCount != 1 {
+		t.Error("Totals changed the file")
+	}
+	if len(totals.Batches) != 2 || totals.Batches[1].BatchNumber != 2 {
+		t.Fatalf("Totals expected 2 batches got: %+v", totals.Batches)
+	}
+	if totals.Batches[0].Net() != 100000000-2500 || totals.Net() != 2*100000000-2500 {
+		t.Errorf("Totals net %d batch net %d", totals.Net(), totals.Batches[0].Net())
+	}
+
+	if err := file.Batches[0].Create(); err != nil {
+		t.Fatalf("%T: %s", err, err)
+	}
+	if err := file.Create(); err != nil {
+		t.Fatalf("%T: %s", err, err)
+	}
+	fc := file.Control
+	if totals.TotalDebit != fc.TotalDebitEntryDollarAmountInFile ||
+		totals.TotalCredit != fc.TotalCreditEntryDollarAmountInFile ||
+		totals.EntryHash != fc.EntryHash ||
+		totals.EntryAddendaCount != fc.EntryAddendaCount {
+		t.Errorf("Totals %+v do not match file control %+v", totals, fc)
+	}
+
+	file.Batches[1].GetEntries()[0].TransactionCode = 0
+	if _, err := file.Totals(); err == nil {
+		t.Error("expected Totals error for a batch that can not be created")
+	}
+}
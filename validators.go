@@ -30,9 +30,52 @@ func (e *FieldError) Error() string {
 	return fmt.Sprintf("%s %s %s", e.FieldName, e.Value, e.Msg)
 }
 
+// ErrorLocation identifies the field that caused a validation or parse error. BatchIndex and
+// EntryIndex are the index of the batch in the file and the entry in the batch, or -1 when
+// they are not known. LineNumber is the line read by a Reader, or 0 when the error was not
+// returned by a Reader.
+type ErrorLocation struct {
+	BatchIndex int
+	EntryIndex int
+	FieldName  string
+	LineNumber int
+}
+
+// AsFieldError returns the location of err and true if err is a FieldError, BatchError,
+// FileError or a ParseError of one of them. Batch validation errors for an entry include the
+// EntryIndex, errors of a batch returned by File.Validate and File.ValidateWith include the
+// BatchIndex, and errors returned by a Reader include the BatchIndex and LineNumber.
+func AsFieldError(err error) (ErrorLocation, bool) {
+	loc := ErrorLocation{BatchIndex: -1, EntryIndex: -1}
+	if e, ok := err.(*ParseError); ok {
+		loc.LineNumber = e.Line
+		loc.BatchIndex = e.batchIndex - 1
+		loc.EntryIndex = e.entryIndex - 1
+		err = e.Err
+	}
+	switch e := err.(type) {
+	case *FieldError:
+		loc.FieldName = e.FieldName
+	case *BatchError:
+		loc.FieldName = e.FieldName
+		if e.batchIndex > 0 {
+			loc.BatchIndex = e.batchIndex - 1
+		}
+		if e.entryIndex > 0 {
+			loc.EntryIndex = e.entryIndex - 1
+		}
+	case *FileError:
+		loc.FieldName = e.FieldName
+	default:
+		return loc, false
+	}
+	return loc, true
+}
+
 // Errors specific to validation
 var (
 	msgAlphanumeric     = "has non alphanumeric characters"
+	msgNumeric          = "has non numeric characters"
 	msgUpperAlpha       = "is not uppercase A-Z or 0-9"
 	msgFieldInclusion   = "is a mandatory field and has a default value"
 	msgValidFieldLength = "is not length %d"
@@ -165,6 +208,14 @@ func (v *validator) isAlphanumeric(s string) error {
 	return nil
 }
 
+// isNumeric checks if a string only contains ASCII numeric (0-9) characters
+func (v *validator) isNumeric(s string) error {
+	if regexp.MustCompile(`[^0-9]`).MatchString(s) {
+		return errors.New(msgNumeric)
+	}
+	return nil
+}
+
 // isOriginatorStatusCode ensures status code is valid
 func (v *validator) isCheckDigit(routingNumber string, checkDigit int) error {
 	calculated := v.CalculateCheckDigit(routingNumber)
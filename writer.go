@@ -6,6 +6,7 @@ package ach
 
 import (
 	"bufio"
+	"context"
 	"io"
 	"strings"
 )
@@ -18,13 +19,63 @@ import (
 type Writer struct {
 	w       *bufio.Writer
 	lineNum int //current line being written
+	// control accumulates the file control of batches written with WriteBatch
+	control FileControl
+	// lineEnding is written after each record
+	lineEnding string
+	// adv is set when a batch written with WriteBatch is an ADV batch
+	adv bool
+	// ctx is checked before each batch and entry is written by WriteContext
+	ctx context.Context
+	// blockingFactor is the number of records in each block
+	blockingFactor int
+}
+
+// LineEnding is the terminator written after each record by a Writer.
+type LineEnding string
+
+// Line endings supported by WriterLineEnding
+const (
+	// LineEndingLF terminates each record with a line feed. This is the default.
+	LineEndingLF LineEnding = "\n"
+	// LineEndingCRLF terminates each record with a carriage return and line feed.
+	LineEndingCRLF LineEnding = "\r\n"
+	// LineEndingNone writes the records as one continuous line.
+	LineEndingNone LineEnding = ""
+)
+
+// WriterOption configures optional Writer behavior and is passed to NewWriter.
+type WriterOption func(*Writer)
+
+// WriterLineEnding sets the terminator written after each record, including the block padding records.
+func WriterLineEnding(le LineEnding) WriterOption {
+	return func(w *Writer) {
+		w.lineEnding = string(le)
+	}
+}
+
+// WriterBlockingFactor pads files to blocks of n records instead of BlockingFactor records for
+// partners that use a different blocking factor. The File Control BlockCount is written as the
+// number of blocks of n records. n less than 1 is ignored.
+func WriterBlockingFactor(n int) WriterOption {
+	return func(w *Writer) {
+		if n > 0 {
+			w.blockingFactor = n
+		}
+	}
 }
 
 // NewWriter returns a new Writer that writes to w.
-func NewWriter(w io.Writer) *Writer {
-	return &Writer{
-		w: bufio.NewWriter(w),
+func NewWriter(w io.Writer, opts ...WriterOption) *Writer {
+	writer := &Writer{
+		w:              bufio.NewWriter(w),
+		lineEnding:     string(LineEndingLF),
+		blockingFactor: BlockingFactor,
+	}
+	for _, opt := range opts {
+		opt(writer)
 	}
+	return writer
 }
 
 // Writer writes a single ach.file record to w
@@ -35,41 +86,179 @@ func (w *Writer) Write(file *File) error {
 
 	w.lineNum = 0
 	// Iterate over all records in the file
-	if _, err := w.w.WriteString(file.Header.String() + "\n"); err != nil {
+	if _, err := w.w.WriteString(file.Header.String() + w.lineEnding); err != nil {
 		return err
 	}
 	w.lineNum++
 
 	for _, batch := range file.Batches {
-		if _, err := w.w.WriteString(batch.GetHeader().String() + "\n"); err != nil {
+		if err := w.writeBatch(batch); err != nil {
+			return err
+		}
+	}
+	return w.writeFileControl(file.Control, file.IsADV())
+}
+
+// WriteContext writes file like Write and stops with the error of ctx once ctx is canceled or
+// its deadline passes. ctx is checked before each batch and entry is written. Records written
+// before ctx is canceled remain in the Writer buffer.
+func (w *Writer) WriteContext(ctx context.Context, file *File) error {
+	w.ctx = ctx
+	defer func() {
+		w.ctx = nil
+	}()
+	return w.Write(file)
+}
+
+// contextErr returns the error of the WriteContext context
+func (w *Writer) contextErr() error {
+	if w.ctx == nil {
+		return nil
+	}
+	return w.ctx.Err()
+}
+
+// WriteFileHeader validates and writes the File Header record to start a file that is written
+// one batch at a time with WriteBatch and completed with Close. Only the current batch is held
+// in memory so files too large to build as a File can be written.
+func (w *Writer) WriteFileHeader(fh FileHeader) error {
+	if err := fh.Validate(); err != nil {
+		return err
+	}
+	w.lineNum = 0
+	w.control = NewFileControl()
+	w.adv = false
+	if _, err := w.w.WriteString(fh.String() + w.lineEnding); err != nil {
+		return err
+	}
+	w.lineNum++
+	return nil
+}
+
+// WriteBatch assigns the next batch number to a created batch, validates and writes it after
+// the File Header written by WriteFileHeader. The batch is added to the file control totals.
+// The batch numbers of a batch that is not valid are left unchanged.
+func (w *Writer) WriteBatch(batch Batcher) error {
+	if w.lineNum == 0 {
+		return &FileError{FieldName: "FileHeader", Msg: msgFileHeader}
+	}
+	header, control := batch.GetHeader(), batch.GetControl()
+	headerNumber, controlNumber := header.BatchNumber, control.BatchNumber
+	header.BatchNumber = w.control.BatchCount + 1
+	control.BatchNumber = w.control.BatchCount + 1
+	if err := batch.Validate(); err != nil {
+		header.BatchNumber, control.BatchNumber = headerNumber, controlNumber
+		return err
+	}
+	w.control.BatchCount++
+	w.control.EntryAddendaCount += batch.GetControl().EntryAddendaCount
+	w.control.EntryHash += batch.GetControl().EntryHash
+	w.control.TotalDebitEntryDollarAmountInFile += batch.GetControl().TotalDebitEntryDollarAmount
+	w.control.TotalCreditEntryDollarAmountInFile += batch.GetControl().TotalCreditEntryDollarAmount
+	if _, ok := batch.(*BatchADV); ok {
+		w.adv = true
+	}
+	return w.writeBatch(batch)
+}
+
+// Close writes the File Control record calculated from the batches written with WriteBatch,
+// pads the final block and calls Flush.
+func (w *Writer) Close() error {
+	if w.lineNum == 0 {
+		return &FileError{FieldName: "FileHeader", Msg: msgFileHeader}
+	}
+	w.control.BlockCount = blockCountFactor(w.lineNum+1, w.blockingFactor)
+	if err := w.writeFileControl(w.control, w.adv); err != nil {
+		return err
+	}
+	w.lineNum = 0
+	w.Flush()
+	return w.Error()
+}
+
+// writeBatch writes the batch header, entries, addenda and batch control records of batch
+func (w *Writer) writeBatch(batch Batcher) error {
+	if err := w.contextErr(); err != nil {
+		return err
+	}
+	if _, err := w.w.WriteString(batch.GetHeader().String() + w.lineEnding); err != nil {
+		return err
+	}
+	w.lineNum++
+	if adv, ok := batch.(*BatchADV); ok {
+		return w.writeADVBatch(adv)
+	}
+	for _, entry := range batch.GetEntries() {
+		if err := w.contextErr(); err != nil {
+			return err
+		}
+		if _, err := w.w.WriteString(entry.String() + w.lineEnding); err != nil {
 			return err
 		}
 		w.lineNum++
-		for _, entry := range batch.GetEntries() {
-			if _, err := w.w.WriteString(entry.String() + "\n"); err != nil {
+		for _, addenda := range entry.Addendum {
+			if _, err := w.w.WriteString(addenda.String() + w.lineEnding); err != nil {
 				return err
 			}
 			w.lineNum++
-			for _, addenda := range entry.Addendum {
-				if _, err := w.w.WriteString(addenda.String() + "\n"); err != nil {
-					return err
-				}
-				w.lineNum++
+		}
+		for _, returnAddenda := range entry.ReturnAddendum {
+			if _, err := w.w.WriteString(returnAddenda.String() + w.lineEnding); err != nil {
+				return err
 			}
+			w.lineNum++
 		}
-		if _, err := w.w.WriteString(batch.GetControl().String() + "\n"); err != nil {
+		if entry.Addenda98 != nil {
+			if _, err := w.w.WriteString(entry.Addenda98.String() + w.lineEnding); err != nil {
+				return err
+			}
+			w.lineNum++
+		}
+	}
+	if _, err := w.w.WriteString(batch.GetControl().String() + w.lineEnding); err != nil {
+		return err
+	}
+	w.lineNum++
+	return nil
+}
+
+// writeADVBatch writes the ADV entries and ADV batch control records of batch
+func (w *Writer) writeADVBatch(batch *BatchADV) error {
+	for _, entry := range batch.GetADVEntries() {
+		if err := w.contextErr(); err != nil {
+			return err
+		}
+		if _, err := w.w.WriteString(entry.String() + w.lineEnding); err != nil {
 			return err
 		}
 		w.lineNum++
 	}
-	if _, err := w.w.WriteString(file.Control.String() + "\n"); err != nil {
+	if _, err := w.w.WriteString(batch.GetADVControl().String() + w.lineEnding); err != nil {
+		return err
+	}
+	w.lineNum++
+	return nil
+}
+
+// writeFileControl writes the File Control record, or the ADV File Control record if adv is
+// set, and pads the final block
+func (w *Writer) writeFileControl(fc FileControl, adv bool) error {
+	if w.blockingFactor != BlockingFactor {
+		fc.BlockCount = blockCountFactor(w.lineNum+1, w.blockingFactor)
+	}
+	record := fc.String()
+	if adv {
+		advControl := advFileControl(fc)
+		record = advControl.String()
+	}
+	if _, err := w.w.WriteString(record + w.lineEnding); err != nil {
 		return err
 	}
 	w.lineNum++
 
 	// pad the final block
-	for i := 0; i < (10-(w.lineNum%10)) && w.lineNum%10 != 0; i++ {
-		if _, err := w.w.WriteString(strings.Repeat("9", 94) + "\n"); err != nil {
+	for i := 0; i < (w.blockingFactor-(w.lineNum%w.blockingFactor)) && w.lineNum%w.blockingFactor != 0; i++ {
+		if _, err := w.w.WriteString(strings.Repeat("9", 94) + w.lineEnding); err != nil {
 			return err
 		}
 	}
@@ -2,6 +2,7 @@ package ach
 
 import (
 	"bytes"
+	"context"
 	"strings"
 	"testing"
 )
@@ -38,3 +39,152 @@ func TestPPDWrite(t *testing.T) {
 		t.Errorf("%T: %s", err, err)
 	}
 }
+
+func TestWriteBatchStream(t *testing.T) {
+	file := mockFilePPD()
+	batch := mockBatchPPD()
+	batch.GetEntries()[0].AddAddenda(mockAddenda())
+	if err := batch.Create(); err != nil {
+		t.Fatalf("%T: %s", err, err)
+	}
+	file.AddBatch(batch)
+	if err := file.Create(); err != nil {
+		t.Fatalf("%T: %s", err, err)
+	}
+	want := &bytes.Buffer{}
+	w := NewWriter(want)
+	if err := w.WriteAll([]*File{file}); err != nil {
+		t.Fatalf("%T: %s", err, err)
+	}
+
+	got := &bytes.Buffer{}
+	w = NewWriter(got)
+	if err := w.WriteBatch(file.Batches[0]); err == nil {
+		t.Error("expected error writing a batch before the file header")
+	}
+	if err := w.WriteFileHeader(file.Header); err != nil {
+		t.Fatalf("%T: %s", err, err)
+	}
+	for _, batch := range file.Batches {
+		if err := w.WriteBatch(batch); err != nil {
+			t.Fatalf("%T: %s", err, err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("%T: %s", err, err)
+	}
+	if got.String() != want.String() {
+		t.Errorf("streamed file does not match written file\ngot:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+// TestWriteBatchInvalidNumbers an invalid batch keeps its batch numbers
+func TestWriteBatchInvalidNumbers(t *testing.T) {
+	file := mockFilePPD()
+	w := NewWriter(&bytes.Buffer{})
+	if err := w.WriteFileHeader(file.Header); err != nil {
+		t.Fatalf("%T: %s", err, err)
+	}
+	batch := mockBatchPPD()
+	batch.GetHeader().BatchNumber = 7
+	batch.GetControl().BatchNumber = 7
+	batch.GetControl().EntryAddendaCount = 5
+	if err := w.WriteBatch(batch); err == nil {
+		t.Error("expected error writing an invalid batch")
+	}
+	if batch.GetHeader().BatchNumber != 7 || batch.GetControl().BatchNumber != 7 {
+		t.Errorf("BatchNumber changed to %d and %d", batch.GetHeader().BatchNumber, batch.GetControl().BatchNumber)
+	}
+	batch.GetControl().EntryAddendaCount = 1
+	if err := w.WriteBatch(batch); err != nil {
+		t.Fatalf("%T: %s", err, err)
+	}
+	if batch.GetHeader().BatchNumber != 1 || batch.GetControl().BatchNumber != 1 {
+		t.Errorf("BatchNumber expected 1 got %d and %d", batch.GetHeader().BatchNumber, batch.GetControl().BatchNumber)
+	}
+}
+
+func TestWriterLineEnding(t *testing.T) {
+	file := mockFilePPD()
+	for _, le := range []LineEnding{LineEndingLF, LineEndingCRLF, LineEndingNone} {
+		b := &bytes.Buffer{}
+		w := NewWriter(b, WriterLineEnding(le))
+		if err := w.WriteAll([]*File{file}); err != nil {
+			t.Fatalf("%T: %s", err, err)
+		}
+		// header, batch header, entry, batch control, file control and 5 padding records
+		if b.Len() != 10*(RecordLength+len(le)) {
+			t.Errorf("%q line ending wrote %d bytes", le, b.Len())
+		}
+		if le != LineEndingNone && !strings.HasSuffix(b.String(), strings.Repeat("9", RecordLength)+string(le)) {
+			t.Errorf("%q line ending not written after padding", le)
+		}
+	}
+
+	b := &bytes.Buffer{}
+	w := NewWriter(b, WriterLineEnding(LineEndingNone))
+	if err := w.WriteAll([]*File{file}); err != nil {
+		t.Fatalf("%T: %s", err, err)
+	}
+	r := NewReader(strings.NewReader(b.String()))
+	if _, err := r.Read(); err != nil {
+		t.Errorf("%T: %s", err, err)
+	}
+	if err := r.File.Validate(); err != nil {
+		t.Errorf("%T: %s", err, err)
+	}
+}
+
+func TestWriterWriteContext(t *testing.T) {
+	file := mockFilePPD()
+	b := &bytes.Buffer{}
+	w := NewWriter(b)
+	if err := w.WriteContext(context.Background(), file); err != nil {
+		t.Fatalf("%T: %s", err, err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	w = NewWriter(&bytes.Buffer{})
+	if err := w.WriteContext(ctx, file); err != context.Canceled {
+		t.Errorf("expected context.Canceled got: %v", err)
+	}
+	// Write is not affected by an earlier canceled WriteContext
+	if err := w.Write(file); err != nil {
+		t.Errorf("%T: %s", err, err)
+	}
+}
+
+func TestWriterBlockingFactor(t *testing.T) {
+	b := &bytes.Buffer{}
+	w := NewWriter(b, WriterBlockingFactor(8))
+	if err := w.WriteAll([]*File{mockFilePPD()}); err != nil {
+		t.Fatalf("%T: %s", err, err)
+	}
+	// header, batch header, entry, batch control, file control and 3 padding records
+	lines := strings.Split(strings.TrimSuffix(b.String(), "\n"), "\n")
+	if len(lines) != 8 {
+		t.Errorf("expected 8 lines got: %d", len(lines))
+	}
+	if lines[4][7:13] != "000001" {
+		t.Errorf("BlockCount Expected 000001 got: %v", lines[4][7:13])
+	}
+	if lines[7] != strings.Repeat("9", RecordLength) {
+		t.Errorf("expected padding record got: %v", lines[7])
+	}
+}
+
+// TestWriterBlockingFactorInvalid ensures a blocking factor less than 1 falls back to BlockingFactor
+func TestWriterBlockingFactorInvalid(t *testing.T) {
+	for _, n := range []int{0, -1} {
+		b := &bytes.Buffer{}
+		w := NewWriter(b, WriterBlockingFactor(n))
+		if err := w.WriteAll([]*File{mockFilePPD()}); err != nil {
+			t.Fatalf("%T: %s", err, err)
+		}
+		lines := strings.Split(strings.TrimSuffix(b.String(), "\n"), "\n")
+		if len(lines) != BlockingFactor {
+			t.Errorf("blocking factor %d expected %d lines got: %d", n, BlockingFactor, len(lines))
+		}
+	}
+}